@@ -1,23 +1,75 @@
-package main
-
-import (
-	"log"
-
-	"github.com/hsibAD/order-service/internal/config"
-	"github.com/hsibAD/order-service/internal/server"
-)
-
-func main() {
-	// Load configuration
-	cfg := config.Load()
-
-	// Create and start server
-	srv, err := server.NewServer(cfg)
-	if err != nil {
-		log.Fatalf("Failed to create server: %v", err)
-	}
-
-	if err := srv.Run(); err != nil {
-		log.Fatalf("Failed to run server: %v", err)
-	}
-} 
\ No newline at end of file
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/hsibAD/order-service/internal/config"
+	"github.com/hsibAD/order-service/internal/logging"
+	"github.com/hsibAD/order-service/internal/server"
+	"github.com/hsibAD/order-service/internal/tracing"
+	"go.uber.org/zap"
+)
+
+func main() {
+	// Load configuration
+	cfg, err := config.Load(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return
+	}
+	if err != nil {
+		log.Fatalf("Invalid configuration:\n%v", err)
+	}
+
+	logger, level, err := logging.New(logging.Config{Level: cfg.Log.Level, Format: cfg.Log.Format})
+	if err != nil {
+		log.Fatalf("Failed to create logger: %v", err)
+	}
+	defer logger.Sync()
+	zap.ReplaceGlobals(logger)
+	zap.RedirectStdLog(logger)
+
+	shutdownTracing, err := tracing.Setup(context.Background(), tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+		ServiceName:  "order-service",
+	})
+	if err != nil {
+		logger.Fatal("Failed to set up tracing", zap.Error(err))
+	}
+	defer shutdownTracing(context.Background())
+
+	// Create and start server
+	srv, err := server.NewServer(cfg, logger)
+	if err != nil {
+		logger.Fatal("Failed to create server", zap.Error(err))
+	}
+
+	// Stops serving on SIGINT or SIGTERM, letting in-flight calls finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Applies rate limit and log level changes on SIGHUP or file change
+	reloader := config.NewReloader(cfg, os.Args[1:])
+	reloader.Subscribe(func(cfg *config.Config) {
+		if err := level.UnmarshalText([]byte(cfg.Log.Level)); err != nil {
+			logger.Error("Failed to change log level", zap.Error(err))
+		}
+		srv.Reload(cfg)
+	})
+	go reloader.Run(logging.NewContext(ctx, logger))
+
+	if err := srv.Run(ctx); err != nil {
+		logger.Error("Failed to run server", zap.Error(err))
+		shutdownTracing(context.Background())
+		logger.Sync()
+		os.Exit(1)
+	}
+}
\ No newline at end of file
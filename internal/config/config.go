@@ -1,50 +1,308 @@
-package config
-
-import (
-	"os"
-	"strconv"
-)
-
-type Config struct {
-	Port           string
-	RedisURL       string
-	RedisPassword  string
-	RedisDB        int
-	MongoURI       string
-	MongoDB        string
-	NatsURL        string
-	JWTSecret      string
-	RateLimit      int
-	RateLimitBurst int
-}
-
-func Load() *Config {
-	return &Config{
-		Port:           getEnv("PORT", "50051"),
-		RedisURL:       getEnv("REDIS_URL", "redis:6379"),
-		RedisPassword:  getEnv("REDIS_PASSWORD", ""),
-		RedisDB:        getEnvAsInt("REDIS_DB", 0),
-		MongoURI:       getEnv("MONGO_URI", "mongodb://mongodb:27017"),
-		MongoDB:        getEnv("MONGO_DB", "orders"),
-		NatsURL:        getEnv("NATS_URL", "nats://nats:4222"),
-		JWTSecret:      getEnv("JWT_SECRET", "your-secret-key"),
-		RateLimit:      getEnvAsInt("RATE_LIMIT", 60),
-		RateLimitBurst: getEnvAsInt("RATE_LIMIT_BURST", 10),
-	}
-}
-
-func getEnv(key, defaultValue string) string {
-	if value, exists := os.LookupEnv(key); exists {
-		return value
-	}
-	return defaultValue
-}
-
-func getEnvAsInt(key string, defaultValue int) int {
-	if value, exists := os.LookupEnv(key); exists {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
-		}
-	}
-	return defaultValue
-} 
\ No newline at end of file
+package config
+
+import (
+	"time"
+)
+
+// Config is assembled from, in increasing precedence: the defaults below, a
+// YAML or TOML file, environment variables and command-line flags. See Load.
+//
+// Every setting has a file key (the yaml/toml tags, nested by section), an
+// environment variable (env) and a flag named after its file path, e.g.
+// -mongo.uri. Settings tagged secret may also be read from the file named by
+// the variable with a _FILE suffix. Settings tagged reload are applied
+// without a restart when the configuration is reloaded.
+type Config struct {
+	File string `yaml:"-" toml:"-"` // The configuration file in use, if any
+
+	Host      string `yaml:"host" toml:"host" env:"HOST"` // Interface to listen on, all of them when empty
+	Port      string `yaml:"port" toml:"port" env:"PORT"`
+	AdminPort string `yaml:"admin_port" toml:"admin_port" env:"ADMIN_PORT"` // HTTP listener for /metrics, /healthz and /readyz
+	HTTPPort  string `yaml:"http_port" toml:"http_port" env:"HTTP_PORT"`    // REST gateway, disabled when empty
+
+	// Where the admin listener binds. Defaults to all interfaces like Host;
+	// set to 127.0.0.1 to keep it off the network.
+	AdminHost string `yaml:"admin_host" toml:"admin_host" env:"ADMIN_HOST"`
+
+	// Registers gRPC reflection and channelz, and serves a debug page on the
+	// admin listener. Not for production: it exposes the API surface and
+	// configuration.
+	DevMode bool `yaml:"dev_mode" toml:"dev_mode" env:"DEV_MODE"`
+
+	// How often the configuration file is checked for changes, 0 reloads on
+	// SIGHUP only
+	ReloadInterval time.Duration `yaml:"reload_interval" toml:"reload_interval" env:"CONFIG_RELOAD_INTERVAL"`
+
+	TLS        TLSConfig        `yaml:"tls" toml:"tls"`
+	Mongo      MongoConfig      `yaml:"mongo" toml:"mongo"`
+	Redis      RedisConfig      `yaml:"redis" toml:"redis"`
+	NATS       NATSConfig       `yaml:"nats" toml:"nats"`
+	SMTP       SMTPConfig       `yaml:"smtp" toml:"smtp"`
+	Auth       AuthConfig       `yaml:"auth" toml:"auth"`
+	Encryption EncryptionConfig `yaml:"encryption" toml:"encryption"`
+	Events     EventsConfig     `yaml:"events" toml:"events"`
+	Cache      CacheConfig      `yaml:"cache" toml:"cache"`
+	Slots      SlotsConfig      `yaml:"slots" toml:"slots"`
+	Pricing    PricingConfig    `yaml:"pricing" toml:"pricing"`
+	Addresses  AddressesConfig  `yaml:"addresses" toml:"addresses"`
+	Geocoder   GeocoderConfig   `yaml:"geocoder" toml:"geocoder"`
+	Log        LogConfig        `yaml:"log" toml:"log"`
+	Tracing    TracingConfig    `yaml:"tracing" toml:"tracing"`
+	Health     HealthConfig     `yaml:"health" toml:"health"`
+	Shutdown   ShutdownConfig   `yaml:"shutdown" toml:"shutdown"`
+	Watch      WatchConfig      `yaml:"watch" toml:"watch"`
+}
+
+// TLSConfig secures the gRPC listener. It serves plaintext without a
+// certificate; with ClientCAFile it requires client certificates (mutual
+// TLS), and ClientRoles grants roles to callers by certificate identity, see
+// auth.PeerIdentity.
+type TLSConfig struct {
+	CertFile       string            `yaml:"cert_file" toml:"cert_file" env:"TLS_CERT_FILE"`
+	KeyFile        string            `yaml:"key_file" toml:"key_file" env:"TLS_KEY_FILE"`
+	ClientCAFile   string            `yaml:"client_ca_file" toml:"client_ca_file" env:"TLS_CLIENT_CA_FILE"`
+	ClientAuth     string            `yaml:"client_auth" toml:"client_auth" env:"TLS_CLIENT_AUTH"` // "require" or "optional"
+	ClientRoles    map[string]string `yaml:"client_roles" toml:"client_roles" env:"TLS_CLIENT_ROLES"`
+	ReloadInterval time.Duration     `yaml:"reload_interval" toml:"reload_interval" env:"TLS_RELOAD_INTERVAL"` // How often the files are checked for changes
+}
+
+// ClientTLSConfig secures the connection to a dependency. The variables are
+// prefixed by the section, e.g. MONGO_TLS_CA_FILE.
+type ClientTLSConfig struct {
+	Enabled    bool   `yaml:"enabled" toml:"enabled" env:"ENABLED"`
+	CAFile     string `yaml:"ca_file" toml:"ca_file" env:"CA_FILE"`
+	CertFile   string `yaml:"cert_file" toml:"cert_file" env:"CERT_FILE"`
+	KeyFile    string `yaml:"key_file" toml:"key_file" env:"KEY_FILE"`
+	ServerName string `yaml:"server_name" toml:"server_name" env:"SERVER_NAME"`
+}
+
+type MongoConfig struct {
+	URI      string          `yaml:"uri" toml:"uri" env:"MONGO_URI" secret:"true"`
+	Database string          `yaml:"database" toml:"database" env:"MONGO_DB"`
+	TLS      ClientTLSConfig `yaml:"tls" toml:"tls" env:"MONGO_TLS_"`
+}
+
+type RedisConfig struct {
+	Addr     string          `yaml:"addr" toml:"addr" env:"REDIS_URL"`
+	Password string          `yaml:"password" toml:"password" env:"REDIS_PASSWORD" secret:"true"`
+	DB       int             `yaml:"db" toml:"db" env:"REDIS_DB"`
+	TLS      ClientTLSConfig `yaml:"tls" toml:"tls" env:"REDIS_TLS_"`
+}
+
+type NATSConfig struct {
+	URL string          `yaml:"url" toml:"url" env:"NATS_URL"`
+	TLS ClientTLSConfig `yaml:"tls" toml:"tls" env:"NATS_TLS_"`
+}
+
+type SMTPConfig struct {
+	Host     string `yaml:"host" toml:"host" env:"SMTP_HOST"`
+	Port     int    `yaml:"port" toml:"port" env:"SMTP_PORT"`
+	Username string `yaml:"username" toml:"username" env:"SMTP_USERNAME"`
+	Password string `yaml:"password" toml:"password" env:"SMTP_PASSWORD" secret:"true"`
+	From     string `yaml:"from" toml:"from" env:"SMTP_FROM"`
+}
+
+type AuthConfig struct {
+	JWTSecret string `yaml:"jwt_secret" toml:"jwt_secret" env:"JWT_SECRET" secret:"true"`
+
+	// Calls per minute per user, or per client address for anonymous calls.
+	// 0 disables rate limiting.
+	RateLimit      int `yaml:"rate_limit" toml:"rate_limit" env:"RATE_LIMIT" reload:"true"`
+	RateLimitBurst int `yaml:"rate_limit_burst" toml:"rate_limit_burst" env:"RATE_LIMIT_BURST" reload:"true"`
+}
+
+// EncryptionConfig holds the field encryption master keys, see
+// encryption.ParseKeys and LoadKeyFile. Without them a single key is derived
+// from Key, which has no default.
+type EncryptionConfig struct {
+	Key              string        `yaml:"key" toml:"key" env:"FIELD_ENCRYPTION_KEY" secret:"true"`
+	Keys             string        `yaml:"keys" toml:"keys" env:"FIELD_ENCRYPTION_KEYS" secret:"true"`
+	KeysFile         string        `yaml:"keys_file" toml:"keys_file" env:"FIELD_ENCRYPTION_KEYS_FILE"`
+	ActiveKey        string        `yaml:"active_key" toml:"active_key" env:"FIELD_ENCRYPTION_ACTIVE_KEY"`
+	RotationInterval time.Duration `yaml:"rotation_interval" toml:"rotation_interval" env:"KEY_ROTATION_INTERVAL"`
+}
+
+// EventsConfig sets the personal data in NATS event payloads, "full" or
+// "minimal" per subject. Every subject is minimal unless configured
+// otherwise.
+type EventsConfig struct {
+	PayloadProfile  string            `yaml:"payload_profile" toml:"payload_profile" env:"EVENT_PAYLOAD_PROFILE"`
+	PayloadProfiles map[string]string `yaml:"payload_profiles" toml:"payload_profiles" env:"EVENT_PAYLOAD_PROFILES"`
+}
+
+type CacheConfig struct {
+	Namespace        string        `yaml:"namespace" toml:"namespace" env:"CACHE_NAMESPACE"`
+	OrderTTL         time.Duration `yaml:"order_ttl" toml:"order_ttl" env:"ORDER_CACHE_TTL"`
+	OrderNegativeTTL time.Duration `yaml:"order_negative_ttl" toml:"order_negative_ttl" env:"ORDER_CACHE_NEGATIVE_TTL"`
+	L1Size           int           `yaml:"l1_size" toml:"l1_size" env:"CACHE_L1_SIZE"`
+	L1TTL            time.Duration `yaml:"l1_ttl" toml:"l1_ttl" env:"CACHE_L1_TTL"`
+	LockTTL          time.Duration `yaml:"lock_ttl" toml:"lock_ttl" env:"LOCK_TTL"`
+}
+
+type SlotsConfig struct {
+	FirstHour       int           `yaml:"first_hour" toml:"first_hour" env:"SLOT_FIRST_HOUR"`
+	LastHour        int           `yaml:"last_hour" toml:"last_hour" env:"SLOT_LAST_HOUR"`
+	Length          time.Duration `yaml:"length" toml:"length" env:"SLOT_LENGTH"`
+	Capacity        int           `yaml:"capacity" toml:"capacity" env:"SLOT_CAPACITY"`
+	HoldTTL         time.Duration `yaml:"hold_ttl" toml:"hold_ttl" env:"SLOT_HOLD_TTL"`
+	CacheTTL        time.Duration `yaml:"cache_ttl" toml:"cache_ttl" env:"SLOT_CACHE_TTL"`
+	SweepInterval   time.Duration `yaml:"sweep_interval" toml:"sweep_interval" env:"SLOT_SWEEP_INTERVAL"`
+	MetricsInterval time.Duration `yaml:"metrics_interval" toml:"metrics_interval" env:"SLOT_METRICS_INTERVAL"`
+}
+
+type PricingConfig struct {
+	BaseFee               float64 `yaml:"base_fee" toml:"base_fee" env:"DELIVERY_BASE_FEE"`
+	SurgeUtilization      float64 `yaml:"surge_utilization" toml:"surge_utilization" env:"SURGE_UTILIZATION"`
+	SurgeMultiplier       float64 `yaml:"surge_multiplier" toml:"surge_multiplier" env:"SURGE_MULTIPLIER"`
+	MinimumOrderTotal     float64 `yaml:"minimum_order_total" toml:"minimum_order_total" env:"MINIMUM_ORDER_TOTAL"`
+	FreeDeliveryThreshold float64 `yaml:"free_delivery_threshold" toml:"free_delivery_threshold" env:"FREE_DELIVERY_THRESHOLD"`
+
+	// Per postal zone overrides of FreeDeliveryThreshold
+	ZoneFreeDeliveryThresholds map[string]float64 `yaml:"zone_free_delivery_thresholds" toml:"zone_free_delivery_thresholds" env:"ZONE_FREE_DELIVERY_THRESHOLDS"`
+}
+
+type AddressesConfig struct {
+	ZonesFile     string        `yaml:"zones_file" toml:"zones_file" env:"DELIVERY_ZONES_FILE"`
+	MergeInterval time.Duration `yaml:"merge_interval" toml:"merge_interval" env:"ADDRESS_MERGE_INTERVAL"`
+}
+
+type GeocoderConfig struct {
+	Provider string        `yaml:"provider" toml:"provider" env:"GEOCODER"` // "static", "http" or empty to disable
+	File     string        `yaml:"file" toml:"file" env:"GEOCODER_FILE"`
+	URL      string        `yaml:"url" toml:"url" env:"GEOCODER_URL"`
+	APIKey   string        `yaml:"api_key" toml:"api_key" env:"GEOCODER_API_KEY" secret:"true"`
+	Timeout  time.Duration `yaml:"timeout" toml:"timeout" env:"GEOCODER_TIMEOUT"`
+	CacheTTL time.Duration `yaml:"cache_ttl" toml:"cache_ttl" env:"GEOCODE_CACHE_TTL"`
+}
+
+// LogConfig mirrors logging.Config.
+type LogConfig struct {
+	Level            string `yaml:"level" toml:"level" env:"LOG_LEVEL" reload:"true"`
+	Format           string `yaml:"format" toml:"format" env:"LOG_FORMAT"`
+	SampleInitial    int    `yaml:"sample_initial" toml:"sample_initial" env:"LOG_SAMPLE_INITIAL"`
+	SampleThereafter int    `yaml:"sample_thereafter" toml:"sample_thereafter" env:"LOG_SAMPLE_THEREAFTER"`
+}
+
+// TracingConfig mirrors tracing.Config.
+type TracingConfig struct {
+	Exporter     string  `yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER"`
+	OTLPEndpoint string  `yaml:"otlp_endpoint" toml:"otlp_endpoint" env:"OTEL_EXPORTER_OTLP_ENDPOINT"`
+	OTLPInsecure bool    `yaml:"otlp_insecure" toml:"otlp_insecure" env:"TRACING_OTLP_INSECURE"`
+	SampleRatio  float64 `yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO"`
+}
+
+// HealthConfig paces the readiness checks of MongoDB, Redis and NATS.
+type HealthConfig struct {
+	Interval time.Duration `yaml:"interval" toml:"interval" env:"HEALTH_CHECK_INTERVAL"`
+	Timeout  time.Duration `yaml:"timeout" toml:"timeout" env:"HEALTH_CHECK_TIMEOUT"`
+}
+
+// ShutdownConfig times graceful shutdown: readiness goes NOT_SERVING
+// DrainDelay before the listener closes, then in-flight calls get until
+// Timeout. The whole shutdown can take DrainDelay plus Timeout, which the
+// orchestrator's grace period must allow for.
+type ShutdownConfig struct {
+	Timeout    time.Duration `yaml:"timeout" toml:"timeout" env:"SHUTDOWN_TIMEOUT"`
+	DrainDelay time.Duration `yaml:"drain_delay" toml:"drain_delay" env:"SHUTDOWN_DRAIN_DELAY"`
+}
+
+// WatchConfig sets how often WatchOrder streams send a heartbeat while the
+// order doesn't change, so proxies keep them open.
+type WatchConfig struct {
+	HeartbeatInterval time.Duration `yaml:"heartbeat_interval" toml:"heartbeat_interval" env:"WATCH_HEARTBEAT_INTERVAL"`
+}
+
+// Default returns the settings used when nothing else is configured.
+func Default() *Config {
+	return &Config{
+		Port:           "50051",
+		AdminPort:      "9090",
+		HTTPPort:       "8080",
+		ReloadInterval: 30 * time.Second,
+
+		TLS: TLSConfig{
+			ClientAuth:     "require",
+			ClientRoles:    map[string]string{},
+			ReloadInterval: time.Minute,
+		},
+		Mongo: MongoConfig{
+			URI:      "mongodb://mongodb:27017",
+			Database: "orders",
+		},
+		Redis: RedisConfig{
+			Addr: "redis:6379",
+		},
+		NATS: NATSConfig{
+			URL: "nats://nats:4222",
+		},
+		SMTP: SMTPConfig{
+			Port: 587,
+		},
+		Auth: AuthConfig{
+			RateLimit:      60,
+			RateLimitBurst: 10,
+		},
+		Encryption: EncryptionConfig{
+			RotationInterval: time.Hour,
+		},
+		Events: EventsConfig{
+			PayloadProfile: "minimal",
+		},
+		Cache: CacheConfig{
+			Namespace:        "order-service",
+			OrderTTL:         5 * time.Minute,
+			OrderNegativeTTL: 30 * time.Second,
+			L1Size:           10000,
+			L1TTL:            30 * time.Second,
+			LockTTL:          10 * time.Second,
+		},
+		Slots: SlotsConfig{
+			FirstHour:       8,
+			LastHour:        20,
+			Length:          2 * time.Hour,
+			Capacity:        20,
+			HoldTTL:         10 * time.Minute,
+			CacheTTL:        time.Minute,
+			SweepInterval:   30 * time.Second,
+			MetricsInterval: time.Minute,
+		},
+		Pricing: PricingConfig{
+			BaseFee:                    4.99,
+			SurgeUtilization:           0.8,
+			SurgeMultiplier:            1.5,
+			MinimumOrderTotal:          10,
+			FreeDeliveryThreshold:      50,
+			ZoneFreeDeliveryThresholds: map[string]float64{},
+		},
+		Addresses: AddressesConfig{
+			MergeInterval: time.Hour,
+		},
+		Geocoder: GeocoderConfig{
+			URL:      "https://nominatim.openstreetmap.org",
+			Timeout:  5 * time.Second,
+			CacheTTL: 30 * 24 * time.Hour,
+		},
+		Log: LogConfig{
+			Level:            "info",
+			Format:           "json",
+			SampleInitial:    100,
+			SampleThereafter: 100,
+		},
+		Tracing: TracingConfig{
+			Exporter:     "none",
+			OTLPEndpoint: "otel-collector:4317",
+			OTLPInsecure: true,
+			SampleRatio:  1,
+		},
+		Health: HealthConfig{
+			Interval: 5 * time.Second,
+			Timeout:  2 * time.Second,
+		},
+		Shutdown: ShutdownConfig{
+			Timeout: 30 * time.Second,
+		},
+		Watch: WatchConfig{
+			HeartbeatInterval: 15 * time.Second,
+		},
+	}
+}
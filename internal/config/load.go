@@ -0,0 +1,328 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// Load builds the configuration from the defaults, the file named by the
+// -config flag or CONFIG_FILE, the environment and the flags in args, in that
+// order of precedence. Every problem found is reported, not only the first.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+	settings := settingsOf(cfg)
+
+	fs := flag.NewFlagSet("order-service", flag.ContinueOnError)
+	file := fs.String("config", os.Getenv("CONFIG_FILE"), "YAML or TOML configuration file (env CONFIG_FILE)")
+	for _, s := range settings {
+		fs.String(s.path, "", "env "+s.env)
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+
+	var errs []error
+	if *file != "" {
+		if err := loadFile(*file, cfg); err != nil {
+			errs = append(errs, err)
+		}
+		cfg.File = *file
+	}
+
+	for _, s := range settings {
+		if err := s.fromEnv(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	fs.Visit(func(f *flag.Flag) {
+		for _, s := range settings {
+			if s.path == f.Name {
+				if err := s.set(f.Value.String()); err != nil {
+					errs = append(errs, fmt.Errorf("flag -%s: %v", f.Name, err))
+				}
+			}
+		}
+	})
+
+	cfg.normalize()
+	errs = append(errs, cfg.validate()...)
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return cfg, nil
+}
+
+// loadFile decodes path over cfg, picking the format by extension. Unknown
+// keys are errors, so typos don't silently fall back to defaults.
+func loadFile(path string, cfg *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %v", err)
+	}
+
+	settings := settingsOf(cfg)
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var root yaml.Node
+		if err := yaml.Unmarshal(data, &root); err != nil {
+			return fmt.Errorf("config file %s: %v", path, err)
+		}
+		if yamlSecondsToDurations(&root, settings) {
+			if data, err = yaml.Marshal(&root); err != nil {
+				return fmt.Errorf("config file %s: %v", path, err)
+			}
+		}
+
+		decoder := yaml.NewDecoder(bytes.NewReader(data))
+		decoder.KnownFields(true)
+		if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+			return fmt.Errorf("config file %s: %v", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(data), cfg)
+		if err != nil {
+			return fmt.Errorf("config file %s: %v", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("config file %s: unknown keys %v", path, undecoded)
+		}
+
+		// Integers were decoded into durations as nanoseconds
+		for _, s := range settings {
+			if s.value.Type() == durationType && meta.Type(strings.Split(s.path, ".")...) == "Integer" {
+				s.value.SetInt(s.value.Int() * int64(time.Second))
+			}
+		}
+	default:
+		return fmt.Errorf("config file %s: unsupported format, use .yaml, .yml or .toml", path)
+	}
+	return nil
+}
+
+// yamlSecondsToDurations rewrites bare integers given for durations as
+// seconds, e.g. 300 as "300s", and reports whether it changed anything.
+func yamlSecondsToDurations(root *yaml.Node, settings []setting) bool {
+	changed := false
+	for _, s := range settings {
+		if s.value.Type() != durationType {
+			continue
+		}
+		if node := yamlLookup(root, s.path); node != nil && node.Kind == yaml.ScalarNode && node.ShortTag() == "!!int" {
+			node.Value += "s"
+			node.Tag = "!!str"
+			changed = true
+		}
+	}
+	return changed
+}
+
+// yamlLookup returns the node at the dotted path, or nil.
+func yamlLookup(node *yaml.Node, path string) *yaml.Node {
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	for _, key := range strings.Split(path, ".") {
+		if node.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == key {
+				next = node.Content[i+1]
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		node = next
+	}
+	return node
+}
+
+func (c *Config) normalize() {
+	// Zones are matched against uppercased postal codes
+	thresholds := make(map[string]float64, len(c.Pricing.ZoneFreeDeliveryThresholds))
+	for zone, threshold := range c.Pricing.ZoneFreeDeliveryThresholds {
+		thresholds[strings.ToUpper(zone)] = threshold
+	}
+	c.Pricing.ZoneFreeDeliveryThresholds = thresholds
+}
+
+// setting is one leaf of Config with its struct tags.
+type setting struct {
+	path   string // Dotted file path, also the flag name
+	env    string
+	secret bool
+	reload bool
+	value  reflect.Value
+}
+
+// settingsOf lists the leaves of cfg in declaration order. The values are
+// addressable, so setting them writes through to cfg. The env tag of a
+// section prefixes the variables of its fields.
+func settingsOf(cfg *Config) []setting {
+	var settings []setting
+	var walk func(v reflect.Value, prefix, envPrefix string)
+	walk = func(v reflect.Value, prefix, envPrefix string) {
+		for i := 0; i < v.NumField(); i++ {
+			field := v.Type().Field(i)
+			name := field.Tag.Get("yaml")
+			if name == "" || name == "-" {
+				continue
+			}
+			if prefix != "" {
+				name = prefix + "." + name
+			}
+
+			env := field.Tag.Get("env")
+			if env != "" {
+				env = envPrefix + env
+			}
+
+			if field.Type.Kind() == reflect.Struct {
+				walk(v.Field(i), name, env)
+				continue
+			}
+			settings = append(settings, setting{
+				path:   name,
+				env:    env,
+				secret: field.Tag.Get("secret") == "true",
+				reload: field.Tag.Get("reload") == "true",
+				value:  v.Field(i),
+			})
+		}
+	}
+	walk(reflect.ValueOf(cfg).Elem(), "", "")
+	return settings
+}
+
+// fromEnv applies the setting's environment variable, or for secrets the
+// contents of the file named by the _FILE variable.
+func (s setting) fromEnv() error {
+	if s.env == "" {
+		return nil
+	}
+
+	value, ok := os.LookupEnv(s.env)
+	if s.secret {
+		if path, fileOK := os.LookupEnv(s.env + "_FILE"); fileOK {
+			if ok {
+				return fmt.Errorf("env %s: set either %s or %s_FILE, not both", s.env, s.env, s.env)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				return fmt.Errorf("env %s_FILE: %v", s.env, err)
+			}
+			value, ok = strings.TrimRight(string(data), "\r\n"), true
+		}
+	}
+	if !ok {
+		return nil
+	}
+
+	if err := s.set(value); err != nil {
+		return fmt.Errorf("env %s: %v", s.env, err)
+	}
+	return nil
+}
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// set parses raw into the setting. Maps are written as "key=value,key=value",
+// durations as "30s" or "5m", or as bare integers of seconds like the
+// settings that predate duration syntax, e.g. ORDER_CACHE_TTL=300.
+func (s setting) set(raw string) error {
+	v := s.value
+	if v.Type() == durationType {
+		if seconds, err := strconv.ParseInt(raw, 10, 64); err == nil {
+			v.SetInt(int64(time.Duration(seconds) * time.Second))
+			return nil
+		}
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q, want e.g. 30s, 5m or a number of seconds", raw)
+		}
+		v.SetInt(int64(d))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Map:
+		m := reflect.MakeMap(v.Type())
+		for _, pair := range strings.Split(raw, ",") {
+			if strings.TrimSpace(pair) == "" {
+				continue
+			}
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok {
+				return fmt.Errorf("invalid pair %q, want key=value", pair)
+			}
+			elem := reflect.New(v.Type().Elem()).Elem()
+			if err := (setting{value: elem}).set(strings.TrimSpace(value)); err != nil {
+				return fmt.Errorf("%s: %v", strings.TrimSpace(key), err)
+			}
+			m.SetMapIndex(reflect.ValueOf(strings.TrimSpace(key)), elem)
+		}
+		v.Set(m)
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
+
+// Value is one setting as shown to operators, see Config.Values.
+type Value struct {
+	Path   string
+	Env    string
+	Value  string
+	Reload bool // Applied without a restart
+}
+
+// Values lists the settings of c in declaration order. Secrets that are set
+// are masked.
+func (c *Config) Values() []Value {
+	settings := settingsOf(c)
+	values := make([]Value, 0, len(settings))
+	for _, s := range settings {
+		value := fmt.Sprint(s.value.Interface())
+		if s.secret && !s.value.IsZero() {
+			value = "********"
+		}
+		values = append(values, Value{Path: s.path, Env: s.env, Value: value, Reload: s.reload})
+	}
+	return values
+}
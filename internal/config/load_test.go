@@ -0,0 +1,243 @@
+package config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testJWTSecret     = "0123456789abcdef0123456789abcdef-jwt"
+	testEncryptionKey = "0123456789abcdef0123456789abcdef-key"
+)
+
+// setSecrets sets the secrets that have no defaults, so Load succeeds.
+func setSecrets(t *testing.T) {
+	t.Helper()
+	t.Setenv("CONFIG_FILE", "")
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	t.Setenv("FIELD_ENCRYPTION_KEY", testEncryptionKey)
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	tests := []struct {
+		name     string
+		file     string
+		env      map[string]string
+		args     []string
+		wantPort string
+		wantTTL  time.Duration
+	}{
+		{
+			name:     "defaults",
+			wantPort: "50051",
+			wantTTL:  5 * time.Minute,
+		},
+		{
+			name:     "file over defaults",
+			file:     "port: \"6000\"\ncache:\n  order_ttl: 10m\n",
+			wantPort: "6000",
+			wantTTL:  10 * time.Minute,
+		},
+		{
+			name:     "env over file",
+			file:     "port: \"6000\"\ncache:\n  order_ttl: 10m\n",
+			env:      map[string]string{"PORT": "7000"},
+			wantPort: "7000",
+			wantTTL:  10 * time.Minute,
+		},
+		{
+			name:     "flags over env",
+			file:     "port: \"6000\"\ncache:\n  order_ttl: 10m\n",
+			env:      map[string]string{"PORT": "7000", "ORDER_CACHE_TTL": "20m"},
+			args:     []string{"-port", "8000"},
+			wantPort: "8000",
+			wantTTL:  20 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setSecrets(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, "config.yaml", tt.file)}, args...)
+			}
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Port != tt.wantPort {
+				t.Errorf("port = %q, want %q", cfg.Port, tt.wantPort)
+			}
+			if cfg.Cache.OrderTTL != tt.wantTTL {
+				t.Errorf("cache.order_ttl = %v, want %v", cfg.Cache.OrderTTL, tt.wantTTL)
+			}
+		})
+	}
+}
+
+func TestLoadDurationsInSeconds(t *testing.T) {
+	tests := []struct {
+		name string
+		file string // Name and content of the config file, if any
+		body string
+		env  map[string]string
+		args []string
+	}{
+		{name: "env", env: map[string]string{"ORDER_CACHE_TTL": "300"}},
+		{name: "flag", args: []string{"-cache.order_ttl", "300"}},
+		{name: "yaml", file: "config.yaml", body: "cache:\n  order_ttl: 300\n"},
+		{name: "toml", file: "config.toml", body: "[cache]\norder_ttl = 300\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setSecrets(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+			args := tt.args
+			if tt.file != "" {
+				args = append([]string{"-config", writeFile(t, tt.file, tt.body)}, args...)
+			}
+
+			cfg, err := Load(args)
+			if err != nil {
+				t.Fatalf("Load: %v", err)
+			}
+			if cfg.Cache.OrderTTL != 300*time.Second {
+				t.Errorf("cache.order_ttl = %v, want 5m0s", cfg.Cache.OrderTTL)
+			}
+		})
+	}
+}
+
+func TestLoadSecretFromFile(t *testing.T) {
+	setSecrets(t)
+	os.Unsetenv("JWT_SECRET")
+	t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt", testJWTSecret+"\n"))
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Auth.JWTSecret != testJWTSecret {
+		t.Errorf("auth.jwt_secret was not read from the file, or kept its newline")
+	}
+
+	// The variable and the file together are ambiguous
+	t.Setenv("JWT_SECRET", testJWTSecret)
+	if _, err := Load(nil); err == nil || !strings.Contains(err.Error(), "JWT_SECRET_FILE") {
+		t.Errorf("Load with both JWT_SECRET and JWT_SECRET_FILE: err = %v", err)
+	}
+}
+
+func TestLoadReportsEveryError(t *testing.T) {
+	tests := []struct {
+		name string
+		env  map[string]string
+		args []string
+		want []string
+	}{
+		{
+			name: "validation",
+			env:  map[string]string{"JWT_SECRET": "changeme", "MONGO_URI": "localhost:27017"},
+			args: []string{"-port", "0", "-log.level", "verbose"},
+			want: []string{"auth.jwt_secret", "mongo.uri", "port", "log.level"},
+		},
+		{
+			name: "parsing and validation",
+			env:  map[string]string{"REDIS_DB": "one", "SLOT_LENGTH": "soon"},
+			args: []string{"-cache.l1_size", "0"},
+			want: []string{"env REDIS_DB", "env SLOT_LENGTH", "cache.l1_size"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			setSecrets(t)
+			for key, value := range tt.env {
+				t.Setenv(key, value)
+			}
+
+			_, err := Load(tt.args)
+			if err == nil {
+				t.Fatal("Load succeeded, want an error")
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("error does not mention %s: %v", want, err)
+				}
+			}
+		})
+	}
+}
+
+func TestReloadAppliesOnlyReloadableSettings(t *testing.T) {
+	setSecrets(t)
+	path := writeFile(t, "config.yaml", "port: \"6000\"\nlog:\n  level: info\nauth:\n  rate_limit: 60\n")
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	reloader := NewReloader(cfg, args)
+	var notified *Config
+	reloader.Subscribe(func(c *Config) { notified = c })
+
+	if err := os.WriteFile(path, []byte("port: \"7000\"\nlog:\n  level: debug\nauth:\n  rate_limit: 30\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reloader.Reload(context.Background())
+
+	current := reloader.Current()
+	if current.Log.Level != "debug" || current.Auth.RateLimit != 30 {
+		t.Errorf("reloadable settings = %q, %d, want debug, 30", current.Log.Level, current.Auth.RateLimit)
+	}
+	if current.Port != "6000" {
+		t.Errorf("port = %q, want 6000 until a restart", current.Port)
+	}
+	if notified != current {
+		t.Error("subscriber was not called with the new configuration")
+	}
+	if cfg.Log.Level != "info" {
+		t.Error("reload modified the configuration it replaced")
+	}
+}
+
+func TestReloadKeepsConfigWhenInvalid(t *testing.T) {
+	setSecrets(t)
+	path := writeFile(t, "config.yaml", "log:\n  level: info\n")
+	args := []string{"-config", path}
+	cfg, err := Load(args)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	reloader := NewReloader(cfg, args)
+
+	if err := os.WriteFile(path, []byte("log:\n  level: verbose\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	reloader.Reload(context.Background())
+
+	if reloader.Current() != cfg {
+		t.Error("an invalid configuration replaced the current one")
+	}
+}
@@ -0,0 +1,145 @@
+package config
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+)
+
+// Reloader re-reads the configuration on SIGHUP and when the configuration
+// file changes. Only settings tagged reload take effect; changes to the
+// others are logged and wait for a restart.
+type Reloader struct {
+	args []string
+
+	mu          sync.Mutex
+	current     *Config
+	modTime     time.Time
+	subscribers []func(*Config)
+}
+
+// NewReloader watches cfg, which was loaded from args.
+func NewReloader(cfg *Config, args []string) *Reloader {
+	r := &Reloader{args: args, current: cfg}
+	r.modTime, _ = fileModTime(cfg.File)
+	return r
+}
+
+// Subscribe registers fn to be called with the new configuration after
+// every reload that changed a reloadable setting.
+func (r *Reloader) Subscribe(fn func(*Config)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.subscribers = append(r.subscribers, fn)
+}
+
+// Current returns the configuration in effect.
+func (r *Reloader) Current() *Config {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.current
+}
+
+// Run reloads on SIGHUP, and every ReloadInterval if the configuration file
+// was modified, until ctx is done.
+func (r *Reloader) Run(ctx context.Context) {
+	hangup := make(chan os.Signal, 1)
+	signal.Notify(hangup, syscall.SIGHUP)
+	defer signal.Stop(hangup)
+
+	var poll <-chan time.Time
+	if interval := r.Current().ReloadInterval; interval > 0 && r.Current().File != "" {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		poll = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hangup:
+			r.Reload(ctx)
+		case <-poll:
+			modTime, err := fileModTime(r.Current().File)
+			if err != nil {
+				logging.FromContext(ctx).Warn("failed to check config file", zap.Error(err))
+				continue
+			}
+			r.mu.Lock()
+			changed := !modTime.Equal(r.modTime)
+			r.mu.Unlock()
+			if changed {
+				r.Reload(ctx)
+			}
+		}
+	}
+}
+
+// Reload loads the configuration again and applies its reloadable settings.
+// An invalid configuration is logged and leaves the current one in effect.
+func (r *Reloader) Reload(ctx context.Context) {
+	logger := logging.FromContext(ctx)
+
+	r.mu.Lock()
+	modTime, _ := fileModTime(r.current.File)
+	r.modTime = modTime
+	r.mu.Unlock()
+
+	loaded, err := Load(r.args)
+	if err != nil {
+		logger.Error("failed to reload config", zap.Error(err))
+		return
+	}
+
+	r.mu.Lock()
+	next := *r.current
+	var applied, pending []string
+	loadedSettings := settingsOf(loaded)
+	for i, s := range settingsOf(&next) {
+		value := loadedSettings[i].value
+		if reflect.DeepEqual(s.value.Interface(), value.Interface()) {
+			continue
+		}
+		if s.reload {
+			s.value.Set(value)
+			applied = append(applied, s.path)
+		} else {
+			pending = append(pending, s.path)
+		}
+	}
+	if len(applied) > 0 {
+		r.current = &next
+	}
+	subscribers := append([]func(*Config){}, r.subscribers...)
+	r.mu.Unlock()
+
+	if len(pending) > 0 {
+		logger.Warn("config changes need a restart", zap.Strings("settings", pending))
+	}
+	if len(applied) == 0 {
+		return
+	}
+	logger.Info("config reloaded", zap.Strings("settings", applied))
+	for _, fn := range subscribers {
+		fn(&next)
+	}
+}
+
+func fileModTime(path string) (time.Time, error) {
+	if path == "" {
+		return time.Time{}, nil
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return info.ModTime(), nil
+}
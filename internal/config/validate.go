@@ -0,0 +1,223 @@
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validate checks the settings together and returns every problem found.
+func (c *Config) validate() []error {
+	var v validator
+
+	v.port("port", c.Port)
+	v.port("admin_port", c.AdminPort)
+	if c.HTTPPort != "" {
+		v.port("http_port", c.HTTPPort)
+	}
+	v.nonNegative("reload_interval", c.ReloadInterval)
+
+	if (c.TLS.CertFile == "") != (c.TLS.KeyFile == "") {
+		v.fail("tls.cert_file", "cert_file and key_file must be set together")
+	}
+	if c.TLS.ClientCAFile != "" && c.TLS.CertFile == "" {
+		v.fail("tls.client_ca_file", "mutual TLS needs cert_file and key_file")
+	}
+	v.oneOf("tls.client_auth", c.TLS.ClientAuth, "require", "optional")
+	for identity, role := range c.TLS.ClientRoles {
+		v.oneOf("tls.client_roles."+identity, role, "customer", "courier", "admin")
+	}
+	v.positive("tls.reload_interval", c.TLS.ReloadInterval)
+	v.clientTLS("mongo.tls", c.Mongo.TLS)
+	v.clientTLS("redis.tls", c.Redis.TLS)
+	v.clientTLS("nats.tls", c.NATS.TLS)
+
+	if !strings.HasPrefix(c.Mongo.URI, "mongodb://") && !strings.HasPrefix(c.Mongo.URI, "mongodb+srv://") {
+		// The URI carries credentials, so it is not echoed back
+		v.fail("mongo.uri", "must start with mongodb:// or mongodb+srv://")
+	}
+	v.required("mongo.database", c.Mongo.Database)
+	v.required("redis.addr", c.Redis.Addr)
+	if c.Redis.DB < 0 {
+		v.fail("redis.db", "must not be negative")
+	}
+	v.required("nats.url", c.NATS.URL)
+	if c.SMTP.Host != "" {
+		v.port("smtp.port", strconv.Itoa(c.SMTP.Port))
+		v.required("smtp.from", c.SMTP.From)
+	}
+
+	v.secret("auth.jwt_secret", c.Auth.JWTSecret, c.DevMode)
+	if c.Auth.RateLimit < 0 {
+		v.fail("auth.rate_limit", "must not be negative")
+	}
+	if c.Auth.RateLimit > 0 && c.Auth.RateLimitBurst < 1 {
+		v.fail("auth.rate_limit_burst", "must be at least 1 when rate limiting is enabled")
+	}
+
+	if c.Encryption.Keys != "" && c.Encryption.KeysFile != "" {
+		v.fail("encryption.keys", "set either keys or keys_file, not both")
+	}
+	if (c.Encryption.Keys == "" && c.Encryption.KeysFile == "") || c.Encryption.Key != "" {
+		// Key is the only key without keys or keys_file, else it opens
+		// values written by the legacy single-key cipher
+		v.secret("encryption.key", c.Encryption.Key, c.DevMode)
+	}
+	v.positive("encryption.rotation_interval", c.Encryption.RotationInterval)
+
+	v.oneOf("events.payload_profile", c.Events.PayloadProfile, "full", "minimal")
+	for subject, profile := range c.Events.PayloadProfiles {
+		v.oneOf("events.payload_profiles."+subject, profile, "full", "minimal")
+	}
+
+	v.required("cache.namespace", c.Cache.Namespace)
+	v.positive("cache.order_ttl", c.Cache.OrderTTL)
+	v.positive("cache.order_negative_ttl", c.Cache.OrderNegativeTTL)
+	if c.Cache.L1Size < 1 {
+		v.fail("cache.l1_size", "must be at least 1")
+	}
+	v.positive("cache.l1_ttl", c.Cache.L1TTL)
+	v.positive("cache.lock_ttl", c.Cache.LockTTL)
+
+	if c.Slots.FirstHour < 0 || c.Slots.LastHour > 24 || c.Slots.FirstHour >= c.Slots.LastHour {
+		v.fail("slots.first_hour", fmt.Sprintf("slots must satisfy 0 <= first_hour < last_hour <= 24, got %d and %d", c.Slots.FirstHour, c.Slots.LastHour))
+	}
+	v.positive("slots.length", c.Slots.Length)
+	if c.Slots.Capacity < 1 {
+		v.fail("slots.capacity", "must be at least 1")
+	}
+	v.positive("slots.hold_ttl", c.Slots.HoldTTL)
+	v.positive("slots.cache_ttl", c.Slots.CacheTTL)
+	v.positive("slots.sweep_interval", c.Slots.SweepInterval)
+	v.positive("slots.metrics_interval", c.Slots.MetricsInterval)
+
+	if c.Pricing.BaseFee < 0 {
+		v.fail("pricing.base_fee", "must not be negative")
+	}
+	if c.Pricing.SurgeUtilization <= 0 || c.Pricing.SurgeUtilization > 1 {
+		v.fail("pricing.surge_utilization", "must be in (0, 1]")
+	}
+	if c.Pricing.SurgeMultiplier < 1 {
+		v.fail("pricing.surge_multiplier", "must be at least 1")
+	}
+	if c.Pricing.MinimumOrderTotal < 0 {
+		v.fail("pricing.minimum_order_total", "must not be negative")
+	}
+	if c.Pricing.FreeDeliveryThreshold < 0 {
+		v.fail("pricing.free_delivery_threshold", "must not be negative")
+	}
+	for zone, threshold := range c.Pricing.ZoneFreeDeliveryThresholds {
+		if threshold < 0 {
+			v.fail("pricing.zone_free_delivery_thresholds."+zone, "must not be negative")
+		}
+	}
+
+	v.positive("addresses.merge_interval", c.Addresses.MergeInterval)
+
+	v.oneOf("geocoder.provider", c.Geocoder.Provider, "", "static", "http")
+	switch c.Geocoder.Provider {
+	case "static":
+		v.required("geocoder.file", c.Geocoder.File)
+	case "http":
+		v.required("geocoder.url", c.Geocoder.URL)
+		v.positive("geocoder.timeout", c.Geocoder.Timeout)
+	}
+	if c.Geocoder.Provider != "" {
+		v.positive("geocoder.cache_ttl", c.Geocoder.CacheTTL)
+	}
+
+	v.oneOf("log.level", c.Log.Level, "debug", "info", "warn", "error")
+	v.oneOf("log.format", c.Log.Format, "json", "console")
+	if c.Log.SampleInitial < 0 || c.Log.SampleThereafter < 0 {
+		v.fail("log.sample_initial", "sampling settings must not be negative")
+	}
+
+	v.oneOf("tracing.exporter", c.Tracing.Exporter, "none", "stdout", "otlp")
+	if c.Tracing.Exporter == "otlp" {
+		v.required("tracing.otlp_endpoint", c.Tracing.OTLPEndpoint)
+	}
+	if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+		v.fail("tracing.sample_ratio", "must be in [0, 1]")
+	}
+
+	v.positive("health.interval", c.Health.Interval)
+	v.positive("health.timeout", c.Health.Timeout)
+	v.positive("shutdown.timeout", c.Shutdown.Timeout)
+	v.nonNegative("shutdown.drain_delay", c.Shutdown.DrainDelay)
+	v.positive("watch.heartbeat_interval", c.Watch.HeartbeatInterval)
+
+	return v.errs
+}
+
+type validator struct {
+	errs []error
+}
+
+func (v *validator) fail(path, reason string) {
+	v.errs = append(v.errs, fmt.Errorf("%s: %s", path, reason))
+}
+
+func (v *validator) required(path, value string) {
+	if value == "" {
+		v.fail(path, "is required")
+	}
+}
+
+// Secrets shipped in examples and older defaults, rejected outside dev mode
+var placeholderSecrets = map[string]bool{
+	"your-secret-key":     true,
+	"your-encryption-key": true,
+	"secret":              true,
+	"changeme":            true,
+}
+
+// Shorter secrets are rejected outside dev mode
+const minSecretLength = 32
+
+// secret requires value and, outside dev mode, that it is neither a known
+// placeholder nor too short to resist guessing. The value is never echoed.
+func (v *validator) secret(path, value string, devMode bool) {
+	switch {
+	case value == "":
+		v.fail(path, "is required")
+	case devMode:
+	case placeholderSecrets[value]:
+		v.fail(path, "is a placeholder, set a random secret (dev_mode allows it)")
+	case len(value) < minSecretLength:
+		v.fail(path, fmt.Sprintf("must be at least %d characters (dev_mode allows shorter)", minSecretLength))
+	}
+}
+
+func (v *validator) port(path, value string) {
+	if n, err := strconv.Atoi(value); err != nil || n < 1 || n > 65535 {
+		v.fail(path, fmt.Sprintf("invalid port %q", value))
+	}
+}
+
+func (v *validator) clientTLS(path string, c ClientTLSConfig) {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		v.fail(path+".cert_file", "cert_file and key_file must be set together")
+	}
+}
+
+func (v *validator) positive(path string, d time.Duration) {
+	if d <= 0 {
+		v.fail(path, "must be positive")
+	}
+}
+
+func (v *validator) nonNegative(path string, d time.Duration) {
+	if d < 0 {
+		v.fail(path, "must not be negative")
+	}
+}
+
+func (v *validator) oneOf(path, value string, allowed ...string) {
+	for _, a := range allowed {
+		if value == a {
+			return
+		}
+	}
+	v.fail(path, fmt.Sprintf("invalid value %q, want one of %q", value, allowed))
+}
@@ -0,0 +1,101 @@
+package debug
+
+import (
+	"html/template"
+	"net/http"
+	"sort"
+
+	"github.com/hsibAD/order-service/internal/config"
+	"github.com/hsibAD/order-service/internal/health"
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+)
+
+var page = template.Must(template.New("debug").Parse(`<!DOCTYPE html>
+<html>
+<head><title>order-service debug</title>
+<style>
+body { font-family: sans-serif; margin: 2em; }
+table { border-collapse: collapse; margin-bottom: 2em; }
+td, th { border: 1px solid #ccc; padding: 0.3em 0.6em; text-align: left; font-family: monospace; }
+.failing { color: #b00; }
+</style>
+</head>
+<body>
+<h1>order-service</h1>
+<p>Development mode: gRPC reflection and channelz are enabled, e.g. <code>grpcurl {{if .TLS}}-insecure{{else}}-plaintext{{end}} localhost:{{.Port}} list</code>.</p>
+
+<h2>Dependencies{{if .Ready}} (ready){{else}} (not ready){{end}}</h2>
+<table>
+<tr><th>Dependency</th><th>Status</th></tr>
+{{range .Dependencies}}<tr><td>{{.Name}}</td><td{{if .Failing}} class="failing"{{end}}>{{.Status}}</td></tr>
+{{end}}</table>
+
+<h2>gRPC services</h2>
+<table>
+<tr><th>Service</th><th>Method</th><th>Streaming</th></tr>
+{{range .Services}}{{$service := .Name}}{{range .Methods}}<tr><td>{{$service}}</td><td>{{.Name}}</td><td>{{if .IsServerStream}}server{{end}} {{if .IsClientStream}}client{{end}}</td></tr>
+{{end}}{{end}}</table>
+
+<h2>Configuration</h2>
+<table>
+<tr><th>Setting</th><th>Environment</th><th>Value</th><th>Reloadable</th></tr>
+{{range .Config}}<tr><td>{{.Path}}</td><td>{{.Env}}</td><td>{{.Value}}</td><td>{{if .Reload}}yes{{end}}</td></tr>
+{{end}}</table>
+</body>
+</html>
+`))
+
+type dependency struct {
+	Name    string
+	Status  string
+	Failing bool
+}
+
+type service struct {
+	Name string
+	grpc.ServiceInfo
+}
+
+// Handler serves a page listing the gRPC services registered on server, the
+// configuration returned by current with secrets masked, and the state of
+// the dependencies checked by monitor. It is meant for the admin listener in
+// development mode only.
+func Handler(server *grpc.Server, current func() *config.Config, monitor *health.Monitor) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cfg := current()
+		ready, results := monitor.Ready()
+
+		dependencies := make([]dependency, 0, len(results))
+		for name, err := range results {
+			d := dependency{Name: name, Status: "ok"}
+			if err != nil {
+				d.Status, d.Failing = err.Error(), true
+			}
+			dependencies = append(dependencies, d)
+		}
+		sort.Slice(dependencies, func(i, j int) bool { return dependencies[i].Name < dependencies[j].Name })
+
+		info := server.GetServiceInfo()
+		services := make([]service, 0, len(info))
+		for name, s := range info {
+			services = append(services, service{Name: name, ServiceInfo: s})
+		}
+		sort.Slice(services, func(i, j int) bool { return services[i].Name < services[j].Name })
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		err := page.Execute(w, struct {
+			Port         string
+			TLS          bool
+			Ready        bool
+			Dependencies []dependency
+			Services     []service
+			Config       []config.Value
+		}{cfg.Port, cfg.TLS.CertFile != "", ready, dependencies, services, cfg.Values()})
+		if err != nil {
+			logging.FromContext(r.Context()).Warn("failed to render debug page", zap.Error(err))
+		}
+	})
+}
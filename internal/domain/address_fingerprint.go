@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// streetAbbreviations maps spelled-out street words to the short form, so
+// "12 Main Street" and "12 Main St." fingerprint the same.
+var streetAbbreviations = map[string]string{
+	"street":     "st",
+	"avenue":     "ave",
+	"av":         "ave",
+	"road":       "rd",
+	"boulevard":  "blvd",
+	"drive":      "dr",
+	"lane":       "ln",
+	"court":      "ct",
+	"place":      "pl",
+	"square":     "sq",
+	"terrace":    "ter",
+	"highway":    "hwy",
+	"parkway":    "pkwy",
+	"north":      "n",
+	"south":      "s",
+	"east":       "e",
+	"west":       "w",
+	"улица":      "ул",
+	"проспект":   "пр",
+	"переулок":   "пер",
+	"микрорайон": "мкр",
+}
+
+// unitDesignators start the apartment part when it's typed into the street
+// line, as in "12 Main St, Apt 3".
+var unitDesignators = map[string]bool{
+	"apt":       true,
+	"apartment": true,
+	"unit":      true,
+	"suite":     true,
+	"ste":       true,
+	"flat":      true,
+	"#":         true,
+	"кв":        true,
+}
+
+// Fingerprint identifies the building an address points at, ignoring case,
+// punctuation, common abbreviations and the apartment. Use IsDuplicateOf to
+// also compare apartments.
+func (a *DeliveryAddress) Fingerprint() string {
+	street, _ := splitUnit(addressTokens(a.StreetAddress))
+	return strings.Join([]string{
+		strings.Join(street, " "),
+		strings.Join(addressTokens(a.City), " "),
+		strings.ReplaceAll(a.PostalCode, " ", ""),
+		strings.ToUpper(a.Country),
+	}, "|")
+}
+
+// IsDuplicateOf reports whether both addresses are the same place and
+// recipient for the same user. An address without an apartment matches one
+// with an apartment, since that's usually the same address typed with less
+// detail. Another recipient at the same place is a different address.
+func (a *DeliveryAddress) IsDuplicateOf(other *DeliveryAddress) bool {
+	if a.UserID != other.UserID || a.Fingerprint() != other.Fingerprint() {
+		return false
+	}
+	if a.Phone != other.Phone || !strings.EqualFold(recipientName(a.FullName), recipientName(other.FullName)) {
+		return false
+	}
+
+	unit, otherUnit := a.unit(), other.unit()
+	return unit == "" || otherUnit == "" || unit == otherUnit
+}
+
+// MergeDuplicate folds a duplicate into a, keeping a's contact details and
+// taking over the apartment, delivery preferences and default flag when a
+// lacks them. It reports whether a changed.
+func (a *DeliveryAddress) MergeDuplicate(duplicate *DeliveryAddress) bool {
+	changed := false
+	if a.unit() == "" && duplicate.unit() != "" {
+		street, _ := splitUnit(strings.Fields(a.StreetAddress))
+		a.StreetAddress = strings.TrimRight(strings.Join(street, " "), ",")
+		a.Apartment = duplicate.Apartment
+		if a.Apartment == "" {
+			// Keep the unit as typed, designator included
+			street, _ := splitUnit(strings.Fields(duplicate.StreetAddress))
+			unit := strings.TrimPrefix(duplicate.StreetAddress, strings.Join(street, " "))
+			a.Apartment = strings.TrimLeft(unit, ", ")
+		}
+		changed = true
+	}
+	if a.Location == nil && duplicate.Location != nil {
+		a.Location = duplicate.Location
+		changed = true
+	}
+	if a.Preferences == (DeliveryPreferences{}) && duplicate.Preferences != (DeliveryPreferences{}) {
+		a.Preferences = duplicate.Preferences
+		changed = true
+	}
+	if duplicate.IsDefault && !a.IsDefault {
+		a.IsDefault = true
+		changed = true
+	}
+	return changed
+}
+
+// unit is the normalized apartment, whether typed separately or as part of
+// the street line.
+func (a *DeliveryAddress) unit() string {
+	if a.Apartment != "" {
+		tokens := addressTokens(a.Apartment)
+		if len(tokens) > 0 && unitDesignators[tokens[0]] {
+			tokens = tokens[1:]
+		}
+		return strings.Join(tokens, " ")
+	}
+
+	_, unit := splitUnit(addressTokens(a.StreetAddress))
+	return strings.Join(unit, " ")
+}
+
+// addressTokens lowercases s, drops punctuation and expands abbreviations.
+func addressTokens(s string) []string {
+	s = strings.ReplaceAll(strings.ToLower(s), "#", " # ")
+	s = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '#' {
+			return r
+		}
+		return ' '
+	}, s)
+
+	tokens := strings.Fields(s)
+	for i, token := range tokens {
+		if short, ok := streetAbbreviations[token]; ok {
+			tokens[i] = short
+		}
+	}
+	return tokens
+}
+
+// splitUnit cuts the tokens at the first unit designator after the house
+// number and street name.
+func splitUnit(tokens []string) (street []string, unit []string) {
+	for i := 1; i < len(tokens); i++ {
+		designator := strings.ToLower(strings.Trim(tokens[i], ",."))
+		if unitDesignators[designator] || strings.HasPrefix(tokens[i], "#") {
+			unit = tokens[i+1:]
+			if designator != "#" && strings.HasPrefix(tokens[i], "#") {
+				unit = append([]string{strings.TrimPrefix(tokens[i], "#")}, unit...)
+			}
+			return tokens[:i], unit
+		}
+	}
+	return tokens, nil
+}
+
+// recipientName folds the spacing of a name; case is ignored by the caller.
+func recipientName(name string) string {
+	return strings.Join(strings.Fields(name), " ")
+}
@@ -0,0 +1,42 @@
+package domain
+
+import "testing"
+
+func savedAddress(t *testing.T, fullName, street, apartment, phone string) *DeliveryAddress {
+	t.Helper()
+	address, err := NewDeliveryAddress("user-1", fullName, street, apartment, "London", "", "NW1 6XE", "GB", phone, false, DeliveryPreferences{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return address
+}
+
+func TestIsDuplicateOf(t *testing.T) {
+	saved := savedAddress(t, "Jane Roe", "221B Baker Street", "Flat 2", "020 7946 0958")
+
+	tests := []struct {
+		name    string
+		address *DeliveryAddress
+		want    bool
+	}{
+		{"same", savedAddress(t, "Jane Roe", "221B Baker Street", "Flat 2", "020 7946 0958"), true},
+		{"abbreviated and spaced", savedAddress(t, "jane  roe", "221b baker st.", "", "+44 20 7946 0958"), true},
+		{"other apartment", savedAddress(t, "Jane Roe", "221B Baker Street", "Flat 3", "020 7946 0958"), false},
+		{"other recipient", savedAddress(t, "John Doe", "221B Baker Street", "Flat 2", "020 7946 0958"), false},
+		{"other phone", savedAddress(t, "Jane Roe", "221B Baker Street", "Flat 2", "020 7946 0000"), false},
+		{"other street", savedAddress(t, "Jane Roe", "10 Baker Street", "Flat 2", "020 7946 0958"), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.address.IsDuplicateOf(saved); got != tt.want {
+				t.Errorf("IsDuplicateOf = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	other := savedAddress(t, "Jane Roe", "221B Baker Street", "Flat 2", "020 7946 0958")
+	other.UserID = "user-2"
+	if other.IsDuplicateOf(saved) {
+		t.Error("addresses of different users are duplicates")
+	}
+}
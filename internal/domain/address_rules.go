@@ -0,0 +1,221 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+	"unicode"
+)
+
+// AddressRules describes how addresses are written in one country.
+type AddressRules struct {
+	Country       string         // ISO 3166-1 alpha-2 code
+	PostalCode    *regexp.Regexp // Matched against the normalized postal code
+	PostalSpaceAt int            // Inserts a space before the last N characters, 0 keeps spacing as typed
+	RequiresState bool
+	CallingCode   string // Without the leading +, empty skips the country check
+	TrunkPrefix   string // Dropped from nationally written numbers, e.g. "0" in 020 7946 0000
+	PhoneDigits   [2]int // Min and max digits after the calling code
+}
+
+// NormalizePostalCode uppercases the code and applies the country's spacing.
+func (r *AddressRules) NormalizePostalCode(postalCode string) string {
+	postalCode = strings.ToUpper(strings.Join(strings.Fields(postalCode), " "))
+	if r.PostalSpaceAt == 0 {
+		return postalCode
+	}
+
+	compact := strings.ReplaceAll(postalCode, " ", "")
+	if len(compact) <= r.PostalSpaceAt {
+		return compact
+	}
+	split := len(compact) - r.PostalSpaceAt
+	return compact[:split] + " " + compact[split:]
+}
+
+// NormalizePhone rewrites phone in E.164 form. Numbers written without an
+// international prefix are assumed to be local to the country. Input that
+// can't be interpreted is returned with only the separators removed.
+func (r *AddressRules) NormalizePhone(phone string) string {
+	var digits strings.Builder
+	for i, c := range strings.TrimSpace(phone) {
+		switch {
+		case c >= '0' && c <= '9':
+			digits.WriteRune(c)
+		case c == '+' && i == 0:
+			digits.WriteRune(c)
+		case c == ' ' || c == '-' || c == '.' || c == '(' || c == ')':
+		default:
+			return phone
+		}
+	}
+
+	number := digits.String()
+	switch {
+	case strings.HasPrefix(number, "+"):
+		return number
+	case strings.HasPrefix(number, "00"):
+		return "+" + number[2:]
+	case r.CallingCode == "" || number == "":
+		return number
+	}
+
+	if r.TrunkPrefix != "" && strings.HasPrefix(number, r.TrunkPrefix) {
+		number = number[len(r.TrunkPrefix):]
+	}
+	return "+" + r.CallingCode + number
+}
+
+func (r *AddressRules) validPhone(phone string) bool {
+	if !phoneRegex.MatchString(phone) {
+		return false
+	}
+	if r.CallingCode == "" {
+		return true
+	}
+
+	national, ok := strings.CutPrefix(phone, "+"+r.CallingCode)
+	if !ok {
+		return false
+	}
+	return len(national) >= r.PhoneDigits[0] && len(national) <= r.PhoneDigits[1]
+}
+
+// AddressRuleRegistry holds the per-country rules. Countries without rules
+// fall back to permissive defaults so new markets aren't blocked outright.
+type AddressRuleRegistry struct {
+	mu       sync.RWMutex
+	rules    map[string]*AddressRules
+	fallback *AddressRules
+}
+
+func NewAddressRuleRegistry(fallback *AddressRules) *AddressRuleRegistry {
+	return &AddressRuleRegistry{
+		rules:    make(map[string]*AddressRules),
+		fallback: fallback,
+	}
+}
+
+func (r *AddressRuleRegistry) Register(rules *AddressRules) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.rules[strings.ToUpper(rules.Country)] = rules
+}
+
+func (r *AddressRuleRegistry) Lookup(country string) *AddressRules {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if rules, ok := r.rules[strings.ToUpper(country)]; ok {
+		return rules
+	}
+	return r.fallback
+}
+
+var addressRules = newDefaultAddressRules()
+
+// RegisterAddressRules adds or replaces the rules used for a country.
+func RegisterAddressRules(rules *AddressRules) {
+	addressRules.Register(rules)
+}
+
+func AddressRulesFor(country string) *AddressRules {
+	return addressRules.Lookup(country)
+}
+
+func newDefaultAddressRules() *AddressRuleRegistry {
+	registry := NewAddressRuleRegistry(&AddressRules{
+		PostalCode: regexp.MustCompile(`^[0-9A-Z][0-9A-Z \-]{1,9}$`),
+	})
+
+	for _, rules := range []*AddressRules{
+		{
+			Country:       "US",
+			PostalCode:    regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+			RequiresState: true,
+			CallingCode:   "1",
+			TrunkPrefix:   "1",
+			PhoneDigits:   [2]int{10, 10},
+		},
+		{
+			Country:       "CA",
+			PostalCode:    regexp.MustCompile(`^[A-Z]\d[A-Z] \d[A-Z]\d$`),
+			PostalSpaceAt: 3,
+			RequiresState: true,
+			CallingCode:   "1",
+			TrunkPrefix:   "1",
+			PhoneDigits:   [2]int{10, 10},
+		},
+		{
+			Country:       "GB",
+			PostalCode:    regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? \d[A-Z]{2}$`),
+			PostalSpaceAt: 3,
+			CallingCode:   "44",
+			TrunkPrefix:   "0",
+			PhoneDigits:   [2]int{9, 10},
+		},
+		{
+			Country:     "DE",
+			PostalCode:  regexp.MustCompile(`^\d{5}$`),
+			CallingCode: "49",
+			TrunkPrefix: "0",
+			PhoneDigits: [2]int{6, 13},
+		},
+		{
+			Country:     "FR",
+			PostalCode:  regexp.MustCompile(`^\d{5}$`),
+			CallingCode: "33",
+			TrunkPrefix: "0",
+			PhoneDigits: [2]int{9, 9},
+		},
+		{
+			Country:       "NL",
+			PostalCode:    regexp.MustCompile(`^\d{4} [A-Z]{2}$`),
+			PostalSpaceAt: 2,
+			CallingCode:   "31",
+			TrunkPrefix:   "0",
+			PhoneDigits:   [2]int{9, 9},
+		},
+		{
+			Country:       "AU",
+			PostalCode:    regexp.MustCompile(`^\d{4}$`),
+			RequiresState: true,
+			CallingCode:   "61",
+			TrunkPrefix:   "0",
+			PhoneDigits:   [2]int{9, 9},
+		},
+		{
+			Country:     "RU",
+			PostalCode:  regexp.MustCompile(`^\d{6}$`),
+			CallingCode: "7",
+			TrunkPrefix: "8",
+			PhoneDigits: [2]int{10, 10},
+		},
+		{
+			Country:     "KZ",
+			PostalCode:  regexp.MustCompile(`^(\d{6}|[A-Z]\d{2}[A-Z]\d[A-Z]\d)$`),
+			CallingCode: "7",
+			TrunkPrefix: "8",
+			PhoneDigits: [2]int{10, 10},
+		},
+	} {
+		registry.Register(rules)
+	}
+
+	return registry
+}
+
+// validName accepts letters from any script plus the separators common in
+// personal names, and must start with a letter.
+func validName(name string) bool {
+	length := 0
+	for i, c := range name {
+		length++
+		switch {
+		case unicode.IsLetter(c) || unicode.Is(unicode.Mn, c):
+		case i > 0 && (c == ' ' || c == '-' || c == '\'' || c == '’' || c == '.'):
+		default:
+			return false
+		}
+	}
+	return length >= 2 && length <= 100
+}
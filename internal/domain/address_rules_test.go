@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNormalizePostalCode(t *testing.T) {
+	tests := []struct {
+		country string
+		input   string
+		want    string
+	}{
+		{"GB", "sw1a1aa", "SW1A 1AA"},
+		{"GB", " nw1  6xe ", "NW1 6XE"},
+		{"CA", "k1a0b1", "K1A 0B1"},
+		{"NL", "1012ab", "1012 AB"},
+		{"US", "94103-1234", "94103-1234"},
+		{"ZZ", "ab  12", "AB 12"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.input, func(t *testing.T) {
+			if got := AddressRulesFor(tt.country).NormalizePostalCode(tt.input); got != tt.want {
+				t.Errorf("NormalizePostalCode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizePhone(t *testing.T) {
+	tests := []struct {
+		country string
+		input   string
+		want    string
+	}{
+		{"GB", "020 7946 0958", "+442079460958"},
+		{"GB", "0044 20 7946 0958", "+442079460958"},
+		{"US", "(415) 555-0132", "+14155550132"},
+		{"US", "1-415-555-0132", "+14155550132"},
+		{"FR", "01.23.45.67.89", "+33123456789"},
+		{"RU", "8 912 345-67-89", "+79123456789"},
+		{"ZZ", "555 0132", "5550132"},
+		{"GB", "call 020 7946 0958", "call 020 7946 0958"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.country+" "+tt.input, func(t *testing.T) {
+			if got := AddressRulesFor(tt.country).NormalizePhone(tt.input); got != tt.want {
+				t.Errorf("NormalizePhone(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDeliveryAddressRules(t *testing.T) {
+	tests := []struct {
+		name                   string
+		fullName, street, city string
+		state, postal, country string
+		phone                  string
+		wantErr                error
+		wantPostal, wantPhone  string
+	}{
+		{name: "GB", fullName: "José Álvarez", street: "10 Downing Street", city: "London", postal: "sw1a2aa", country: "gb", phone: "020 7946 0958",
+			wantPostal: "SW1A 2AA", wantPhone: "+442079460958"},
+		{name: "US", fullName: "Jane Roe", street: "1 Market Street", city: "San Francisco", state: "CA", postal: "94105", country: "US", phone: "(415) 555-0132",
+			wantPostal: "94105", wantPhone: "+14155550132"},
+		{name: "DE with non-ASCII street", fullName: "Jürgen Groß", street: "Straße 1", city: "Köln", postal: "50667", country: "DE", phone: "0221 123456",
+			wantPostal: "50667", wantPhone: "+49221123456"},
+		{name: "GB postal code", fullName: "Jane Roe", street: "10 Downing Street", city: "London", postal: "12345", country: "GB", phone: "020 7946 0958",
+			wantErr: ErrInvalidPostalCode},
+		{name: "US without state", fullName: "Jane Roe", street: "1 Market Street", city: "San Francisco", postal: "94105", country: "US", phone: "(415) 555-0132",
+			wantErr: ErrInvalidState},
+		{name: "phone of another country", fullName: "Jane Roe", street: "10 Downing Street", city: "London", postal: "SW1A 2AA", country: "GB", phone: "+1 415 555 0132",
+			wantErr: ErrInvalidPhone},
+		{name: "name with digits", fullName: "J0hn", street: "10 Downing Street", city: "London", postal: "SW1A 2AA", country: "GB", phone: "020 7946 0958",
+			wantErr: ErrInvalidFullName},
+		// Lengths count characters, not bytes
+		{name: "short multibyte street", fullName: "Jane Roe", street: "Ōmi 1", city: "London", postal: "SW1A 2AA", country: "GB", phone: "020 7946 0958"},
+		{name: "street under 5 characters", fullName: "Jane Roe", street: "Ōe 1", city: "London", postal: "SW1A 2AA", country: "GB", phone: "020 7946 0958",
+			wantErr: ErrInvalidStreetAddress},
+		{name: "city of one multibyte character", fullName: "Jane Roe", street: "10 Downing Street", city: "Ö", postal: "SW1A 2AA", country: "GB", phone: "020 7946 0958",
+			wantErr: ErrInvalidCity},
+		{name: "state of one multibyte character", fullName: "Jane Roe", street: "1 Market Street", city: "San Francisco", state: "É", postal: "94105", country: "US", phone: "(415) 555-0132",
+			wantErr: ErrInvalidState},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address, err := NewDeliveryAddress("user-1", tt.fullName, tt.street, "", tt.city, tt.state, tt.postal, tt.country, tt.phone, false, DeliveryPreferences{})
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("err = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewDeliveryAddress: %v", err)
+			}
+			if tt.wantPostal != "" && address.PostalCode != tt.wantPostal {
+				t.Errorf("postal code = %q, want %q", address.PostalCode, tt.wantPostal)
+			}
+			if tt.wantPhone != "" && address.Phone != tt.wantPhone {
+				t.Errorf("phone = %q, want %q", address.Phone, tt.wantPhone)
+			}
+		})
+	}
+}
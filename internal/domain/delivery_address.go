@@ -1,152 +1,250 @@
-package domain
-
-import (
-	"errors"
-	"regexp"
-	"strings"
-)
-
-var (
-	ErrInvalidAddressID    = errors.New("invalid address ID")
-	ErrInvalidFullName     = errors.New("invalid full name")
-	ErrInvalidStreetAddress = errors.New("invalid street address")
-	ErrInvalidCity         = errors.New("invalid city")
-	ErrInvalidState        = errors.New("invalid state")
-	ErrInvalidPostalCode   = errors.New("invalid postal code")
-	ErrInvalidCountry      = errors.New("invalid country")
-	ErrInvalidPhone        = errors.New("invalid phone number")
-)
-
-type DeliveryAddress struct {
-	ID            string
-	UserID        string
-	FullName      string
-	StreetAddress string
-	Apartment     string // Optional
-	City          string
-	State         string
-	PostalCode    string
-	Country       string
-	Phone         string
-	IsDefault     bool
-}
-
-// Regular expressions for validation
-var (
-	phoneRegex    = regexp.MustCompile(`^\+?[1-9]\d{1,14}$`)
-	postalRegex   = regexp.MustCompile(`^[0-9A-Z]{3,10}$`)
-	nameRegex     = regexp.MustCompile(`^[a-zA-Z\s\-']{2,100}$`)
-)
-
-func NewDeliveryAddress(
-	userID string,
-	fullName string,
-	streetAddress string,
-	apartment string,
-	city string,
-	state string,
-	postalCode string,
-	country string,
-	phone string,
-	isDefault bool,
-) (*DeliveryAddress, error) {
-	address := &DeliveryAddress{
-		UserID:        userID,
-		FullName:      strings.TrimSpace(fullName),
-		StreetAddress: strings.TrimSpace(streetAddress),
-		Apartment:     strings.TrimSpace(apartment),
-		City:          strings.TrimSpace(city),
-		State:         strings.TrimSpace(state),
-		PostalCode:    strings.TrimSpace(postalCode),
-		Country:       strings.TrimSpace(country),
-		Phone:         strings.TrimSpace(phone),
-		IsDefault:     isDefault,
-	}
-
-	if err := address.Validate(); err != nil {
-		return nil, err
-	}
-
-	return address, nil
-}
-
-func (a *DeliveryAddress) Validate() error {
-	if a.UserID == "" {
-		return ErrInvalidUserID
-	}
-
-	if !nameRegex.MatchString(a.FullName) {
-		return ErrInvalidFullName
-	}
-
-	if len(a.StreetAddress) < 5 {
-		return ErrInvalidStreetAddress
-	}
-
-	if len(a.City) < 2 {
-		return ErrInvalidCity
-	}
-
-	if len(a.State) < 2 {
-		return ErrInvalidState
-	}
-
-	if !postalRegex.MatchString(a.PostalCode) {
-		return ErrInvalidPostalCode
-	}
-
-	if len(a.Country) < 2 {
-		return ErrInvalidCountry
-	}
-
-	if !phoneRegex.MatchString(a.Phone) {
-		return ErrInvalidPhone
-	}
-
-	return nil
-}
-
-func (a *DeliveryAddress) Update(
-	fullName string,
-	streetAddress string,
-	apartment string,
-	city string,
-	state string,
-	postalCode string,
-	country string,
-	phone string,
-) error {
-	a.FullName = strings.TrimSpace(fullName)
-	a.StreetAddress = strings.TrimSpace(streetAddress)
-	a.Apartment = strings.TrimSpace(apartment)
-	a.City = strings.TrimSpace(city)
-	a.State = strings.TrimSpace(state)
-	a.PostalCode = strings.TrimSpace(postalCode)
-	a.Country = strings.TrimSpace(country)
-	a.Phone = strings.TrimSpace(phone)
-
-	return a.Validate()
-}
-
-func (a *DeliveryAddress) SetDefault(isDefault bool) {
-	a.IsDefault = isDefault
-}
-
-func (a *DeliveryAddress) FormatFull() string {
-	parts := []string{
-		a.StreetAddress,
-	}
-
-	if a.Apartment != "" {
-		parts = append(parts, a.Apartment)
-	}
-
-	parts = append(parts,
-		a.City,
-		a.State,
-		a.PostalCode,
-		a.Country,
-	)
-
-	return strings.Join(parts, ", ")
-} 
\ No newline at end of file
+package domain
+
+import (
+	"errors"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrInvalidAddressID    = errors.New("invalid address ID")
+	ErrInvalidFullName     = errors.New("invalid full name")
+	ErrInvalidStreetAddress = errors.New("invalid street address")
+	ErrInvalidCity         = errors.New("invalid city")
+	ErrInvalidState        = errors.New("invalid state")
+	ErrInvalidPostalCode   = errors.New("invalid postal code")
+	ErrInvalidCountry      = errors.New("invalid country")
+	ErrInvalidPhone        = errors.New("invalid phone number")
+
+	ErrInvalidInstructions  = errors.New("delivery instructions are too long")
+	ErrInvalidFloor         = errors.New("invalid floor")
+	ErrInvalidContactMethod = errors.New("invalid contact method")
+	ErrInvalidAccessCode    = errors.New("invalid access code")
+)
+
+type ContactMethod string
+
+const (
+	ContactMethodCall      ContactMethod = "CALL"
+	ContactMethodSMS       ContactMethod = "SMS"
+	ContactMethodNoContact ContactMethod = "NO_CONTACT"
+)
+
+type DeliveryAddress struct {
+	ID            string
+	UserID        string
+	FullName      string
+	StreetAddress string
+	Apartment     string // Optional
+	City          string
+	State         string
+	PostalCode    string
+	Country       string
+	Phone         string
+	IsDefault     bool
+	Location      *GeoPoint // Geocoded, nil when the address couldn't be located
+	Preferences   DeliveryPreferences
+}
+
+// DeliveryPreferences tell the courier how to hand over the order.
+type DeliveryPreferences struct {
+	Instructions  string // Free text, e.g. "ring twice"
+	LeaveAtDoor   bool
+	Floor         string
+	HasElevator   bool
+	ContactMethod ContactMethod // Empty when the user has no preference
+	AccessCode    string        // Gate or door code, encrypted at rest and only shown to the assigned courier
+}
+
+// Limits for the free text delivery preferences, in characters
+const (
+	maxInstructionsLength = 500
+	maxFloorLength        = 10
+	maxAccessCodeLength   = 32
+)
+
+// Placeholders written over personal data on erasure requests
+const (
+	ErasedFullName      = "Erased User"
+	ErasedStreetAddress = "Erased"
+)
+
+// Regular expressions for validation
+var (
+	phoneRegex   = regexp.MustCompile(`^\+[1-9]\d{6,14}$`)
+	countryRegex = regexp.MustCompile(`^[A-Z]{2}$`)
+)
+
+func NewDeliveryAddress(
+	userID string,
+	fullName string,
+	streetAddress string,
+	apartment string,
+	city string,
+	state string,
+	postalCode string,
+	country string,
+	phone string,
+	isDefault bool,
+	preferences DeliveryPreferences,
+) (*DeliveryAddress, error) {
+	address := &DeliveryAddress{
+		UserID:        userID,
+		FullName:      fullName,
+		StreetAddress: streetAddress,
+		Apartment:     apartment,
+		City:          city,
+		State:         state,
+		PostalCode:    postalCode,
+		Country:       country,
+		Phone:         phone,
+		IsDefault:     isDefault,
+		Preferences:   preferences,
+	}
+	address.Normalize()
+
+	if err := address.Validate(); err != nil {
+		return nil, err
+	}
+
+	return address, nil
+}
+
+// Normalize tidies user input into the stored form: collapsed whitespace,
+// an uppercase country code, the country's postal code format and an E.164
+// phone number.
+func (a *DeliveryAddress) Normalize() {
+	a.FullName = collapseSpaces(a.FullName)
+	a.StreetAddress = collapseSpaces(a.StreetAddress)
+	a.Apartment = collapseSpaces(a.Apartment)
+	a.City = collapseSpaces(a.City)
+	a.State = collapseSpaces(a.State)
+	a.Country = strings.ToUpper(strings.TrimSpace(a.Country))
+
+	rules := AddressRulesFor(a.Country)
+	a.PostalCode = rules.NormalizePostalCode(a.PostalCode)
+	a.Phone = rules.NormalizePhone(a.Phone)
+
+	a.Preferences.Instructions = strings.TrimSpace(a.Preferences.Instructions)
+	a.Preferences.Floor = strings.TrimSpace(a.Preferences.Floor)
+	a.Preferences.ContactMethod = ContactMethod(strings.ToUpper(strings.TrimSpace(string(a.Preferences.ContactMethod))))
+	a.Preferences.AccessCode = strings.TrimSpace(a.Preferences.AccessCode)
+}
+
+// Validate checks the address against the rules of its country. It expects
+// a normalized address and returns a *ValidationError listing every bad field.
+func (a *DeliveryAddress) Validate() error {
+	var v violations
+	v.check(a.UserID != "", "user_id", ErrInvalidUserID)
+	v.check(validName(a.FullName), "full_name", ErrInvalidFullName)
+	v.check(utf8.RuneCountInString(a.StreetAddress) >= 5, "street", ErrInvalidStreetAddress)
+	v.check(utf8.RuneCountInString(a.City) >= 2, "city", ErrInvalidCity)
+	v.check(countryRegex.MatchString(a.Country), "country", ErrInvalidCountry)
+
+	rules := AddressRulesFor(a.Country)
+	v.check(!(rules.RequiresState || a.State != "") || utf8.RuneCountInString(a.State) >= 2, "state", ErrInvalidState)
+	v.check(rules.PostalCode.MatchString(a.PostalCode), "postal_code", ErrInvalidPostalCode)
+	v.check(rules.validPhone(a.Phone), "phone", ErrInvalidPhone)
+
+	p := a.Preferences
+	v.check(utf8.RuneCountInString(p.Instructions) <= maxInstructionsLength, "preferences.instructions", ErrInvalidInstructions)
+	v.check(utf8.RuneCountInString(p.Floor) <= maxFloorLength, "preferences.floor", ErrInvalidFloor)
+	v.check(p.ContactMethod.valid(), "preferences.contact_method", ErrInvalidContactMethod)
+	v.check(utf8.RuneCountInString(p.AccessCode) <= maxAccessCodeLength, "preferences.access_code", ErrInvalidAccessCode)
+
+	return v.err()
+}
+
+func (m ContactMethod) valid() bool {
+	switch m {
+	case "", ContactMethodCall, ContactMethodSMS, ContactMethodNoContact:
+		return true
+	}
+	return false
+}
+
+// WithoutAccessCode returns a copy of the address safe to show to anyone but
+// the courier.
+func (a *DeliveryAddress) WithoutAccessCode() *DeliveryAddress {
+	if a == nil || a.Preferences.AccessCode == "" {
+		return a
+	}
+
+	redacted := *a
+	redacted.Preferences.AccessCode = ""
+	return &redacted
+}
+
+func (a *DeliveryAddress) Update(
+	fullName string,
+	streetAddress string,
+	apartment string,
+	city string,
+	state string,
+	postalCode string,
+	country string,
+	phone string,
+) error {
+	a.FullName = fullName
+	a.StreetAddress = streetAddress
+	a.Apartment = apartment
+	a.City = city
+	a.State = state
+	a.PostalCode = postalCode
+	a.Country = country
+	a.Phone = phone
+	a.Normalize()
+
+	return a.Validate()
+}
+
+// Pseudonymize strips the fields that identify the recipient while keeping
+// the locality, so historical orders stay usable for accounting.
+func (a *DeliveryAddress) Pseudonymize() {
+	a.FullName = ErasedFullName
+	a.StreetAddress = ErasedStreetAddress
+	a.Apartment = ""
+	a.Phone = ""
+	a.Location = nil
+	a.Preferences = DeliveryPreferences{}
+}
+
+// GeocodeQuery is the single-line form of the address sent to geocoders. It
+// leaves out the recipient and apartment, which don't change the location.
+func (a *DeliveryAddress) GeocodeQuery() string {
+	parts := []string{a.StreetAddress, a.City}
+	if a.State != "" {
+		parts = append(parts, a.State)
+	}
+	parts = append(parts, a.PostalCode, a.Country)
+
+	return strings.Join(parts, ", ")
+}
+
+func (a *DeliveryAddress) SetDefault(isDefault bool) {
+	a.IsDefault = isDefault
+}
+
+func (a *DeliveryAddress) FormatFull() string {
+	parts := []string{
+		a.StreetAddress,
+	}
+
+	if a.Apartment != "" {
+		parts = append(parts, a.Apartment)
+	}
+
+	parts = append(parts,
+		a.City,
+		a.State,
+		a.PostalCode,
+		a.Country,
+	)
+
+	return strings.Join(parts, ", ")
+}
+
+func collapseSpaces(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
\ No newline at end of file
@@ -0,0 +1,74 @@
+package domain
+
+import (
+	"errors"
+	"math"
+	"strings"
+)
+
+var ErrBelowMinimumOrder = errors.New("order total is below the minimum for delivery")
+
+// DeliveryPricing decides what a delivery costs. Slots get more expensive
+// once they fill up, and baskets above the free-delivery threshold of their
+// zone ship for free.
+type DeliveryPricing struct {
+	BaseFee           float64
+	SurgeUtilization  float64 // Booked/capacity ratio at which surge applies
+	SurgeMultiplier   float64
+	MinimumOrderTotal float64
+
+	// Free delivery above this basket total, zero disables it. Zones keyed by
+	// postal code prefix override the default; the longest prefix wins.
+	FreeDeliveryThreshold      float64
+	ZoneFreeDeliveryThresholds map[string]float64
+}
+
+// SurgeFor returns the multiplier applied to the slot's base fee.
+func (p DeliveryPricing) SurgeFor(slot *DeliverySlot) float64 {
+	if slot.Capacity <= 0 || p.SurgeMultiplier <= 1 {
+		return 1
+	}
+
+	utilization := float64(slot.Booked) / float64(slot.Capacity)
+	if utilization >= p.SurgeUtilization {
+		return p.SurgeMultiplier
+	}
+	return 1
+}
+
+func (p DeliveryPricing) SlotFee(slot *DeliverySlot) float64 {
+	return roundPrice(p.BaseFee * p.SurgeFor(slot))
+}
+
+func (p DeliveryPricing) FreeDeliveryThresholdFor(postalCode string) float64 {
+	postalCode = strings.ToUpper(strings.ReplaceAll(postalCode, " ", ""))
+
+	threshold := p.FreeDeliveryThreshold
+	matched := -1
+	for prefix, zoneThreshold := range p.ZoneFreeDeliveryThresholds {
+		if strings.HasPrefix(postalCode, prefix) && len(prefix) > matched {
+			threshold = zoneThreshold
+			matched = len(prefix)
+		}
+	}
+
+	return threshold
+}
+
+// OrderDeliveryFee is the fee charged for delivering a basket worth
+// itemsTotal to postalCode in slot.
+func (p DeliveryPricing) OrderDeliveryFee(slot *DeliverySlot, postalCode string, itemsTotal float64) (float64, error) {
+	if itemsTotal < p.MinimumOrderTotal {
+		return 0, ErrBelowMinimumOrder
+	}
+
+	if threshold := p.FreeDeliveryThresholdFor(postalCode); threshold > 0 && itemsTotal >= threshold {
+		return 0, nil
+	}
+
+	return p.SlotFee(slot), nil
+}
+
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
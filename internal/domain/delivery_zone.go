@@ -0,0 +1,168 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrAddressNotServiceable = errors.New("address is outside our delivery area")
+	ErrLocationNotFound      = errors.New("address location not found")
+)
+
+// UnserviceableError explains why an address can't be delivered to.
+type UnserviceableError struct {
+	Reason string
+}
+
+func (e *UnserviceableError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrAddressNotServiceable, e.Reason)
+}
+
+func (e *UnserviceableError) Unwrap() error {
+	return ErrAddressNotServiceable
+}
+
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// Polygon is an outer ring followed by optional holes.
+type Polygon [][]GeoPoint
+
+// DeliveryZone is an area we deliver to. An address belongs to the zone when
+// its postal code is listed or starts with one of the prefixes; when the zone
+// has areas and the address has coordinates, they must fall inside one too.
+type DeliveryZone struct {
+	ID             string
+	Name           string
+	Country        string // ISO 3166 code, empty matches any country
+	PostalCodes    []string
+	PostalPrefixes []string
+	Areas          []Polygon
+}
+
+type Serviceability struct {
+	Serviceable bool
+	ZoneID      string
+	Reason      string // Set when not serviceable
+}
+
+// ZoneCatalog answers serviceability questions. An empty catalog means zones
+// are not configured and every address is accepted.
+type ZoneCatalog struct {
+	zones []*DeliveryZone
+}
+
+func NewZoneCatalog(zones []*DeliveryZone) *ZoneCatalog {
+	for _, zone := range zones {
+		for i, code := range zone.PostalCodes {
+			zone.PostalCodes[i] = normalizePostalCode(code)
+		}
+		for i, prefix := range zone.PostalPrefixes {
+			zone.PostalPrefixes[i] = normalizePostalCode(prefix)
+		}
+	}
+
+	return &ZoneCatalog{
+		zones: zones,
+	}
+}
+
+// Check finds the zone serving postalCode in country. location is optional.
+func (c *ZoneCatalog) Check(postalCode string, country string, location *GeoPoint) Serviceability {
+	if len(c.zones) == 0 {
+		return Serviceability{Serviceable: true}
+	}
+
+	postalCode = normalizePostalCode(postalCode)
+	var outside *DeliveryZone
+	for _, zone := range c.zones {
+		if zone.Country != "" && !strings.EqualFold(zone.Country, country) {
+			continue
+		}
+		if !zone.coversPostalCode(postalCode) {
+			continue
+		}
+		if location != nil && len(zone.Areas) > 0 && !zone.contains(*location) {
+			outside = zone
+			continue
+		}
+		return Serviceability{Serviceable: true, ZoneID: zone.ID}
+	}
+
+	if outside != nil {
+		return Serviceability{
+			Reason: fmt.Sprintf("location is outside the delivery area of zone %s", outside.Name),
+		}
+	}
+
+	return Serviceability{
+		Reason: fmt.Sprintf("no delivery zone covers postal code %s in %s", postalCode, country),
+	}
+}
+
+// Ensure returns an UnserviceableError when the address can't be served.
+func (c *ZoneCatalog) Ensure(address *DeliveryAddress, location *GeoPoint) error {
+	result := c.Check(address.PostalCode, address.Country, location)
+	if !result.Serviceable {
+		return &UnserviceableError{Reason: result.Reason}
+	}
+	return nil
+}
+
+func (z *DeliveryZone) coversPostalCode(postalCode string) bool {
+	for _, code := range z.PostalCodes {
+		if code == postalCode {
+			return true
+		}
+	}
+	for _, prefix := range z.PostalPrefixes {
+		if strings.HasPrefix(postalCode, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (z *DeliveryZone) contains(point GeoPoint) bool {
+	for _, polygon := range z.Areas {
+		if len(polygon) == 0 || !ringContains(polygon[0], point) {
+			continue
+		}
+
+		inHole := false
+		for _, hole := range polygon[1:] {
+			if ringContains(hole, point) {
+				inHole = true
+				break
+			}
+		}
+		if !inHole {
+			return true
+		}
+	}
+	return false
+}
+
+// ringContains is the even-odd ray casting test, treating coordinates as
+// planar, which is accurate enough at city scale.
+func ringContains(ring []GeoPoint, point GeoPoint) bool {
+	inside := false
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		a, b := ring[i], ring[j]
+		if (a.Latitude > point.Latitude) != (b.Latitude > point.Latitude) {
+			crossing := (b.Longitude-a.Longitude)*(point.Latitude-a.Latitude)/(b.Latitude-a.Latitude) + a.Longitude
+			if point.Longitude < crossing {
+				inside = !inside
+			}
+		}
+	}
+	return inside
+}
+
+func normalizePostalCode(postalCode string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(postalCode), " ", ""))
+}
@@ -1,151 +1,207 @@
-package domain
-
-import (
-	"errors"
-	"time"
-)
-
-var (
-	ErrInvalidOrderID      = errors.New("invalid order ID")
-	ErrInvalidUserID       = errors.New("invalid user ID")
-	ErrEmptyItems          = errors.New("order must have at least one item")
-	ErrInvalidTotalPrice   = errors.New("invalid total price")
-	ErrInvalidDeliveryTime = errors.New("invalid delivery time")
-)
-
-type OrderStatus string
-
-const (
-	OrderStatusCreated          OrderStatus = "CREATED"
-	OrderStatusAwaitingPayment  OrderStatus = "AWAITING_PAYMENT"
-	OrderStatusPaid             OrderStatus = "PAID"
-	OrderStatusProcessing       OrderStatus = "PROCESSING"
-	OrderStatusReadyForDelivery OrderStatus = "READY_FOR_DELIVERY"
-	OrderStatusOutForDelivery   OrderStatus = "OUT_FOR_DELIVERY"
-	OrderStatusDelivered        OrderStatus = "DELIVERED"
-	OrderStatusCancelled        OrderStatus = "CANCELLED"
-)
-
-type Order struct {
-	ID              string
-	UserID          string
-	Items           []OrderItem
-	TotalPrice      float64
-	Currency        string
-	Status          OrderStatus
-	DeliveryAddress *DeliveryAddress
-	DeliveryTime    time.Time
-	CreatedAt       time.Time
-	UpdatedAt       time.Time
-}
-
-type OrderItem struct {
-	ProductID   string
-	ProductName string
-	Quantity    int32
-	UnitPrice   float64
-	TotalPrice  float64
-}
-
-type DeliveryAddress struct {
-	ID         string
-	UserID     string
-	Street     string
-	City       string
-	State      string
-	Country    string
-	PostalCode string
-}
-
-type DeliverySlot struct {
-	StartTime time.Time
-	EndTime   time.Time
-	Available bool
-}
-
-func NewOrder(userID string, items []OrderItem, deliveryAddress *DeliveryAddress, deliveryTime time.Time) (*Order, error) {
-	if userID == "" {
-		return nil, ErrInvalidUserID
-	}
-
-	if len(items) == 0 {
-		return nil, ErrEmptyItems
-	}
-
-	totalPrice := 0.0
-	for _, item := range items {
-		totalPrice += item.TotalPrice
-	}
-
-	if totalPrice <= 0 {
-		return nil, ErrInvalidTotalPrice
-	}
-
-	if deliveryTime.Before(time.Now()) {
-		return nil, ErrInvalidDeliveryTime
-	}
-
-	return &Order{
-		UserID:          userID,
-		Items:           items,
-		TotalPrice:      totalPrice,
-		Currency:        "USD", // Default currency
-		Status:          OrderStatusCreated,
-		DeliveryAddress: deliveryAddress,
-		DeliveryTime:    deliveryTime,
-		CreatedAt:       time.Now(),
-		UpdatedAt:       time.Now(),
-	}, nil
-}
-
-func (o *Order) UpdateStatus(status OrderStatus) {
-	o.Status = status
-	o.UpdatedAt = time.Now()
-}
-
-func (o *Order) UpdateDeliveryTime(deliveryTime time.Time) error {
-	if deliveryTime.Before(time.Now()) {
-		return ErrInvalidDeliveryTime
-	}
-
-	o.DeliveryTime = deliveryTime
-	o.UpdatedAt = time.Now()
-	return nil
-}
-
-func (o *Order) UpdateDeliveryAddress(address *DeliveryAddress) {
-	o.DeliveryAddress = address
-	o.UpdatedAt = time.Now()
-}
-
-func (o *Order) CanBePaid() bool {
-	return o.Status == OrderStatusCreated || o.Status == OrderStatusAwaitingPayment
-}
-
-func (o *Order) CanBeCancelled() bool {
-	return o.Status != OrderStatusDelivered && o.Status != OrderStatusCancelled
-}
-
-func (o *Order) MarkAsPaid() {
-	if o.CanBePaid() {
-		o.Status = OrderStatusPaid
-		o.UpdatedAt = time.Now()
-	}
-}
-
-func (o *Order) MarkAsAwaitingPayment() {
-	if o.Status == OrderStatusCreated {
-		o.Status = OrderStatusAwaitingPayment
-		o.UpdatedAt = time.Now()
-	}
-}
-
-func (o *Order) Cancel() error {
-	if !o.CanBeCancelled() {
-		return errors.New("order cannot be cancelled in current status")
-	}
-
-	o.Status = OrderStatusCancelled
-	o.UpdatedAt = time.Now()
-	return nil
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidOrderID      = errors.New("invalid order ID")
+	ErrInvalidUserID       = errors.New("invalid user ID")
+	ErrEmptyItems          = errors.New("order must have at least one item")
+	ErrInvalidTotalPrice   = errors.New("invalid total price")
+	ErrInvalidDeliveryTime = errors.New("invalid delivery time")
+	ErrInvalidCourierID    = errors.New("invalid courier ID")
+	ErrOrderClosed         = errors.New("order is already delivered or cancelled")
+)
+
+type OrderStatus string
+
+const (
+	OrderStatusCreated          OrderStatus = "CREATED"
+	OrderStatusAwaitingPayment  OrderStatus = "AWAITING_PAYMENT"
+	OrderStatusPaid             OrderStatus = "PAID"
+	OrderStatusProcessing       OrderStatus = "PROCESSING"
+	OrderStatusReadyForDelivery OrderStatus = "READY_FOR_DELIVERY"
+	OrderStatusOutForDelivery   OrderStatus = "OUT_FOR_DELIVERY"
+	OrderStatusDelivered        OrderStatus = "DELIVERED"
+	OrderStatusCancelled        OrderStatus = "CANCELLED"
+)
+
+type Order struct {
+	ID              string
+	UserID          string
+	Items           []OrderItem
+	DeliveryFee     float64 // Included in TotalPrice
+	TotalPrice      float64
+	Currency        string
+	Status          OrderStatus
+	DeliveryAddress *DeliveryAddress
+	DeliveryTime    time.Time
+	CourierID       string // Empty until a courier is assigned
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+type OrderItem struct {
+	ProductID   string
+	ProductName string
+	Quantity    int32
+	UnitPrice   float64
+	TotalPrice  float64
+}
+
+type DeliverySlot struct {
+	ID        string
+	StartTime time.Time
+	EndTime   time.Time
+	Available bool
+	Capacity  int
+	Booked    int // Reserved orders plus active holds
+
+	DeliveryFee     float64
+	SurgeMultiplier float64
+}
+
+func NewOrder(userID string, items []OrderItem, deliveryAddress *DeliveryAddress, deliveryTime time.Time, deliveryFee float64) (*Order, error) {
+	var v violations
+	v.checkOrderInput(userID, items, deliveryTime)
+	v.check(deliveryFee >= 0, "delivery_fee", ErrInvalidTotalPrice)
+
+	if err := v.err(); err != nil {
+		return nil, err
+	}
+
+	return &Order{
+		UserID:          userID,
+		Items:           items,
+		DeliveryFee:     deliveryFee,
+		TotalPrice:      roundPrice(ItemsTotal(items) + deliveryFee),
+		Currency:        "USD", // Default currency
+		Status:          OrderStatusCreated,
+		DeliveryAddress: deliveryAddress,
+		DeliveryTime:    deliveryTime,
+		CreatedAt:       time.Now(),
+		UpdatedAt:       time.Now(),
+	}, nil
+}
+
+// ValidateOrderInput checks the fields a client supplies for a new order, so
+// they can be rejected before the delivery is priced. It returns a
+// *ValidationError listing every bad field.
+func ValidateOrderInput(userID string, items []OrderItem, deliveryTime time.Time) error {
+	var v violations
+	v.checkOrderInput(userID, items, deliveryTime)
+	return v.err()
+}
+
+func (v *violations) checkOrderInput(userID string, items []OrderItem, deliveryTime time.Time) {
+	v.check(userID != "", "user_id", ErrInvalidUserID)
+	v.check(len(items) > 0, "items", ErrEmptyItems)
+	v.check(len(items) == 0 || ItemsTotal(items) > 0, "items", ErrInvalidTotalPrice)
+	v.check(!deliveryTime.Before(time.Now()), "delivery_time", ErrInvalidDeliveryTime)
+}
+
+// ItemsTotal sums the item prices, before delivery.
+func ItemsTotal(items []OrderItem) float64 {
+	total := 0.0
+	for _, item := range items {
+		total += item.TotalPrice
+	}
+	return total
+}
+
+func (o *Order) UpdateStatus(status OrderStatus) {
+	o.Status = status
+	o.UpdatedAt = time.Now()
+}
+
+func (o *Order) UpdateDeliveryTime(deliveryTime time.Time) error {
+	if deliveryTime.Before(time.Now()) {
+		return ErrInvalidDeliveryTime
+	}
+
+	o.DeliveryTime = deliveryTime
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+func (o *Order) UpdateDeliveryAddress(address *DeliveryAddress) {
+	o.DeliveryAddress = address
+	o.UpdatedAt = time.Now()
+}
+
+// AssignCourier hands the order to courierID, replacing any earlier courier.
+func (o *Order) AssignCourier(courierID string) error {
+	if courierID == "" {
+		return ErrInvalidCourierID
+	}
+	if o.IsFinal() {
+		return ErrOrderClosed
+	}
+
+	o.CourierID = courierID
+	o.UpdatedAt = time.Now()
+	return nil
+}
+
+// WithoutAccessCode returns a copy of the order whose address snapshot has
+// no access code, for everyone but the courier.
+func (o *Order) WithoutAccessCode() *Order {
+	if o.DeliveryAddress == nil || o.DeliveryAddress.Preferences.AccessCode == "" {
+		return o
+	}
+
+	redacted := *o
+	redacted.DeliveryAddress = o.DeliveryAddress.WithoutAccessCode()
+	return &redacted
+}
+
+// PseudonymizeDeliveryAddress erases the recipient details from the order's
+// address snapshot. Items and prices are left untouched.
+func (o *Order) PseudonymizeDeliveryAddress() {
+	if o.DeliveryAddress == nil {
+		return
+	}
+
+	o.DeliveryAddress.Pseudonymize()
+	o.UpdatedAt = time.Now()
+}
+
+func (o *Order) CanBePaid() bool {
+	return o.Status == OrderStatusCreated || o.Status == OrderStatusAwaitingPayment
+}
+
+func (o *Order) CanBeCancelled() bool {
+	return o.Status != OrderStatusDelivered && o.Status != OrderStatusCancelled
+}
+
+// IsFinal reports whether the order is delivered or cancelled, after which
+// its status no longer changes.
+func (o *Order) IsFinal() bool {
+	return o.Status == OrderStatusDelivered || o.Status == OrderStatusCancelled
+}
+
+func (o *Order) MarkAsPaid() {
+	if o.CanBePaid() {
+		o.Status = OrderStatusPaid
+		o.UpdatedAt = time.Now()
+	}
+}
+
+func (o *Order) MarkAsAwaitingPayment() {
+	if o.Status == OrderStatusCreated {
+		o.Status = OrderStatusAwaitingPayment
+		o.UpdatedAt = time.Now()
+	}
+}
+
+func (o *Order) Cancel() error {
+	if !o.CanBeCancelled() {
+		return errors.New("order cannot be cancelled in current status")
+	}
+
+	o.Status = OrderStatusCancelled
+	o.UpdatedAt = time.Now()
+	return nil
 } 
\ No newline at end of file
@@ -0,0 +1,114 @@
+package domain
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// RedactedAddress is the part of a DeliveryAddress that is safe to log: the
+// locality, without anything that identifies the recipient or the building.
+type RedactedAddress struct {
+	ID         string `json:"id,omitempty"`
+	UserID     string `json:"user_id"`
+	City       string `json:"city"`
+	PostalCode string `json:"postal_code"` // Outward part only
+	Country    string `json:"country"`
+}
+
+// RedactedOrder is an Order safe to log.
+type RedactedOrder struct {
+	ID              string           `json:"id"`
+	UserID          string           `json:"user_id"`
+	Status          OrderStatus      `json:"status"`
+	ItemCount       int              `json:"item_count"`
+	TotalPrice      float64          `json:"total_price"`
+	Currency        string           `json:"currency"`
+	DeliveryTime    time.Time        `json:"delivery_time"`
+	DeliveryAddress *RedactedAddress `json:"delivery_address,omitempty"`
+}
+
+func (a *DeliveryAddress) Redacted() *RedactedAddress {
+	if a == nil {
+		return nil
+	}
+
+	return &RedactedAddress{
+		ID:         a.ID,
+		UserID:     a.UserID,
+		City:       a.City,
+		PostalCode: RedactPostalCode(a.PostalCode),
+		Country:    a.Country,
+	}
+}
+
+func (o *Order) Redacted() *RedactedOrder {
+	if o == nil {
+		return nil
+	}
+
+	return &RedactedOrder{
+		ID:              o.ID,
+		UserID:          o.UserID,
+		Status:          o.Status,
+		ItemCount:       len(o.Items),
+		TotalPrice:      o.TotalPrice,
+		Currency:        o.Currency,
+		DeliveryTime:    o.DeliveryTime,
+		DeliveryAddress: o.DeliveryAddress.Redacted(),
+	}
+}
+
+// String and GoString keep addresses that end up in a format verb by
+// accident from printing personal data.
+func (a *DeliveryAddress) String() string {
+	if a == nil {
+		return "<nil>"
+	}
+	return fmt.Sprintf("DeliveryAddress{ID:%s UserID:%s City:%s PostalCode:%s Country:%s}",
+		a.ID, a.UserID, a.City, RedactPostalCode(a.PostalCode), a.Country)
+}
+
+func (a *DeliveryAddress) GoString() string {
+	return a.String()
+}
+
+// RedactPostalCode keeps the outward part of a postal code, the part before
+// the space, or the first three characters of codes without one.
+func RedactPostalCode(postalCode string) string {
+	if outward, _, ok := strings.Cut(postalCode, " "); ok {
+		return outward + " ***"
+	}
+	if len(postalCode) > 3 {
+		return postalCode[:3] + "***"
+	}
+	return postalCode
+}
+
+var (
+	emailPattern = regexp.MustCompile(`[^\s@"'<>]+@[^\s@"'<>]+\.[A-Za-z]{2,}`)
+	phonePattern = regexp.MustCompile(`(?:\+|\()?\b\d[\d \-().]{5,}\d\b`)
+)
+
+// minPhoneDigits keeps shorter numbers, like quantities and years, readable.
+const minPhoneDigits = 7
+
+// RedactText masks email addresses and phone-like digit runs in free text
+// such as error messages from drivers that echo document values. Digits
+// inside identifiers, like ObjectIDs, are left alone.
+func RedactText(text string) string {
+	text = emailPattern.ReplaceAllString(text, "[email]")
+	return phonePattern.ReplaceAllStringFunc(text, func(match string) string {
+		digits := 0
+		for _, c := range match {
+			if c >= '0' && c <= '9' {
+				digits++
+			}
+		}
+		if digits < minPhoneDigits {
+			return match
+		}
+		return "[phone]"
+	})
+}
@@ -0,0 +1,116 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Personal data of the test recipient, none of which may show up in
+// redacted output.
+var personalData = []string{"Jane Roe", "221B Baker Street", "Flat 2", "+44 20 7946 0958", "4711#", "NW1 6XE"}
+
+func testOrder() *Order {
+	return &Order{
+		ID:         "order-1",
+		UserID:     "user-1",
+		Items:      []OrderItem{{ProductID: "p1", ProductName: "Milk", Quantity: 2, UnitPrice: 1.5, TotalPrice: 3}},
+		TotalPrice: 3,
+		Currency:   "GBP",
+		Status:     OrderStatusCreated,
+		DeliveryAddress: &DeliveryAddress{
+			ID:            "address-1",
+			UserID:        "user-1",
+			FullName:      "Jane Roe",
+			StreetAddress: "221B Baker Street",
+			Apartment:     "Flat 2",
+			City:          "London",
+			PostalCode:    "NW1 6XE",
+			Country:       "GB",
+			Phone:         "+44 20 7946 0958",
+			Preferences:   DeliveryPreferences{AccessCode: "4711#"},
+		},
+		DeliveryTime: time.Date(2030, 1, 2, 10, 0, 0, 0, time.UTC),
+	}
+}
+
+func assertNoPersonalData(t *testing.T, what, text string) {
+	t.Helper()
+	for _, value := range personalData {
+		if strings.Contains(text, value) {
+			t.Errorf("%s contains %q: %s", what, value, text)
+		}
+	}
+}
+
+func TestOrderRedacted(t *testing.T) {
+	order := testOrder()
+	redacted := order.Redacted()
+
+	assertNoPersonalData(t, "redacted order", fmt.Sprintf("%+v %+v", redacted, redacted.DeliveryAddress))
+	if redacted.DeliveryAddress.City != "London" || redacted.DeliveryAddress.PostalCode != "NW1 ***" {
+		t.Errorf("redacted address lost its locality: %+v", redacted.DeliveryAddress)
+	}
+	if redacted.ItemCount != 1 || redacted.TotalPrice != 3 {
+		t.Errorf("redacted order lost its totals: %+v", redacted)
+	}
+}
+
+func TestRedactedNil(t *testing.T) {
+	var order *Order
+	if order.Redacted() != nil {
+		t.Error("nil order should redact to nil")
+	}
+	if (&Order{}).Redacted().DeliveryAddress != nil {
+		t.Error("order without address should have no redacted address")
+	}
+}
+
+func TestDeliveryAddressFormatting(t *testing.T) {
+	address := testOrder().DeliveryAddress
+
+	for _, verb := range []string{"%v", "%+v", "%s", "%#v"} {
+		assertNoPersonalData(t, verb, fmt.Sprintf(verb, address))
+	}
+	// Orders embed the address pointer, so printing an order is safe too
+	assertNoPersonalData(t, "order", fmt.Sprintf("%+v", *testOrder()))
+}
+
+func TestRedactPostalCode(t *testing.T) {
+	tests := []struct {
+		postalCode string
+		want       string
+	}{
+		{"NW1 6XE", "NW1 ***"},
+		{"10115", "101***"},
+		{"K1A", "K1A"},
+		{"", ""},
+	}
+	for _, test := range tests {
+		if got := RedactPostalCode(test.postalCode); got != test.want {
+			t.Errorf("RedactPostalCode(%q) = %q, want %q", test.postalCode, got, test.want)
+		}
+	}
+}
+
+func TestRedactText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{
+			`E11000 duplicate key error dup key: { phone: "+44 20 7946 0958" }`,
+			`E11000 duplicate key error dup key: { phone: "[phone]" }`,
+		},
+		{"write failed for jane.roe@example.com", "write failed for [email]"},
+		{"call (030) 1234-5678 now", "call [phone] now"},
+		{"order 64b7f0c2e13a4d5f6a7b8c9d not found", "order 64b7f0c2e13a4d5f6a7b8c9d not found"},
+		{"quantity 12 in 2024", "quantity 12 in 2024"},
+	}
+	for _, test := range tests {
+		if got := RedactText(test.text); got != test.want {
+			t.Errorf("RedactText(%q) = %q, want %q", test.text, got, test.want)
+		}
+	}
+}
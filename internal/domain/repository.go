@@ -1,47 +1,88 @@
-package domain
-
-import "context"
-
-type OrderRepository interface {
-	Create(ctx context.Context, order *Order) error
-	GetByID(ctx context.Context, id string) (*Order, error)
-	GetByUserID(ctx context.Context, userID string, page, limit int) ([]*Order, int, error)
-	Update(ctx context.Context, order *Order) error
-	UpdateStatus(ctx context.Context, orderID string, status OrderStatus) error
-	Delete(ctx context.Context, id string) error
-}
-
-type DeliveryAddressRepository interface {
-	Create(ctx context.Context, address *DeliveryAddress) error
-	GetByID(ctx context.Context, id string) (*DeliveryAddress, error)
-	GetByUserID(ctx context.Context, userID string) ([]*DeliveryAddress, error)
-	Update(ctx context.Context, address *DeliveryAddress) error
-	Delete(ctx context.Context, id string) error
-	SetDefault(ctx context.Context, userID string, addressID string) error
-}
-
-type DeliverySlotRepository interface {
-	GetAvailableSlots(ctx context.Context, date string) ([]*DeliverySlot, error)
-	ReserveSlot(ctx context.Context, orderID string, slotID string) error
-	ReleaseSlot(ctx context.Context, orderID string, slotID string) error
-}
-
-type DeliverySlot struct {
-	ID        string
-	StartTime string
-	EndTime   string
-	Available bool
-	OrderID   string // If reserved
-}
-
-type Cache interface {
-	Set(ctx context.Context, key string, value interface{}, ttl int) error
-	Get(ctx context.Context, key string) (interface{}, error)
-	Delete(ctx context.Context, key string) error
-}
-
-type EventPublisher interface {
-	PublishOrderCreated(ctx context.Context, order *Order) error
-	PublishOrderStatusUpdated(ctx context.Context, order *Order) error
-	PublishOrderCancelled(ctx context.Context, order *Order) error
-} 
\ No newline at end of file
+package domain
+
+import (
+	"context"
+	"time"
+)
+
+type OrderRepository interface {
+	Create(ctx context.Context, order *Order) error
+	GetByID(ctx context.Context, id string) (*Order, error)
+	GetByUserID(ctx context.Context, userID string, page, limit int) ([]*Order, int, error)
+	Update(ctx context.Context, order *Order) error
+	UpdateStatus(ctx context.Context, orderID string, status OrderStatus) error
+	Delete(ctx context.Context, id string) error
+}
+
+type DeliveryAddressRepository interface {
+	Create(ctx context.Context, address *DeliveryAddress) error
+	GetByID(ctx context.Context, id string) (*DeliveryAddress, error)
+	GetByUserID(ctx context.Context, userID string) ([]*DeliveryAddress, error)
+	// GetByFingerprint lists the user's addresses with the given Fingerprint
+	// and phone
+	GetByFingerprint(ctx context.Context, userID string, fingerprint string, phone string) ([]*DeliveryAddress, error)
+	Update(ctx context.Context, address *DeliveryAddress) error
+	Delete(ctx context.Context, id string) error
+	SetDefault(ctx context.Context, userID string, addressID string) error
+	ListUserIDsWithMultipleAddresses(ctx context.Context) ([]string, error)
+}
+
+type DeliverySlotRepository interface {
+	GetReservationCounts(ctx context.Context, slotIDs []string) (map[string]int, error)
+	// ReserveSlot and Fence take the fencing token of the slot lock held by
+	// the caller and fail with ErrStaleLock once a later token was used
+	ReserveSlot(ctx context.Context, orderID string, slotID string, fence int64) error
+	Fence(ctx context.Context, slotID string, fence int64) error
+	ReleaseSlot(ctx context.Context, orderID string, slotID string) error
+}
+
+type SlotHoldRepository interface {
+	Create(ctx context.Context, hold *SlotHold) error
+	GetByToken(ctx context.Context, token string) (*SlotHold, error)
+	Delete(ctx context.Context, token string) error
+	CountActive(ctx context.Context, slotIDs []string, now time.Time) (map[string]int, error)
+	// ClaimExpired removes and returns one expired hold, or nil if none is left
+	ClaimExpired(ctx context.Context, now time.Time) (*SlotHold, error)
+}
+
+// Cache is a typed key/value cache. Get reports false on a miss; a zero ttl
+// keeps the entry until it is evicted.
+type Cache[T any] interface {
+	Get(ctx context.Context, key string) (T, bool, error)
+	Set(ctx context.Context, key string, value T, ttl time.Duration) error
+	Delete(ctx context.Context, keys ...string) error
+	MGet(ctx context.Context, keys ...string) (map[string]T, error)
+	MSet(ctx context.Context, values map[string]T, ttl time.Duration) error
+}
+
+type EventPublisher interface {
+	PublishOrderCreated(ctx context.Context, order *Order) error
+	PublishOrderStatusUpdated(ctx context.Context, order *Order) error
+	PublishOrderCancelled(ctx context.Context, order *Order) error
+	PublishCourierAssigned(ctx context.Context, order *Order) error
+	PublishUserDataErased(ctx context.Context, userID string, orderIDs []string) error
+	PublishDeliverySlotHoldExpired(ctx context.Context, hold *SlotHold) error
+}
+
+// OrderChange reports that an order changed, as published by any replica.
+// Versions increase with every published order event, so a watcher can
+// resume after the last version it saw.
+type OrderChange struct {
+	OrderID string
+	Version uint64
+	Event   string // Event type, e.g. "OrderStatusUpdated"
+}
+
+type OrderChangeFeed interface {
+	// Watch delivers the changes to orderID after version since, or after
+	// the latest version when since is 0, until ctx is done. It returns the
+	// version delivery starts after, which differs from since when the
+	// changes in between are no longer retained.
+	Watch(ctx context.Context, orderID string, since uint64) (uint64, <-chan OrderChange, error)
+}
+
+// Geocoder resolves an address to coordinates, returning ErrLocationNotFound
+// when the provider has no match.
+type Geocoder interface {
+	Geocode(ctx context.Context, address *DeliveryAddress) (*GeoPoint, error)
+}
\ No newline at end of file
@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidSlotID    = errors.New("invalid delivery slot ID")
+	ErrSlotUnavailable  = errors.New("delivery slot is fully booked")
+	ErrInvalidHoldToken = errors.New("invalid or expired slot hold token")
+	ErrHoldSlotMismatch = errors.New("delivery time does not match the held slot")
+	ErrStaleLock        = errors.New("delivery slot was changed by a newer lock holder")
+)
+
+// SlotHold reserves capacity in a delivery slot for a user during checkout.
+// It is consumed by order creation or released once it expires.
+type SlotHold struct {
+	Token     string
+	SlotID    string
+	UserID    string
+	ExpiresAt time.Time
+}
+
+func (h *SlotHold) IsExpired(now time.Time) bool {
+	return !now.Before(h.ExpiresAt)
+}
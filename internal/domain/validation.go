@@ -0,0 +1,71 @@
+package domain
+
+import (
+	"errors"
+	"strings"
+)
+
+// FieldViolation ties a validation failure to the input field that caused
+// it. Field names follow the API, e.g. "postal_code".
+type FieldViolation struct {
+	Field string
+	Err   error
+}
+
+// ValidationError reports every invalid field at once. It unwraps to the
+// individual sentinel errors, so errors.Is keeps working for callers that
+// only care about one of them.
+type ValidationError struct {
+	Violations []FieldViolation
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		messages[i] = violation.Field + ": " + violation.Err.Error()
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+func (e *ValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Violations))
+	for i, violation := range e.Violations {
+		errs[i] = violation.Err
+	}
+	return errs
+}
+
+// NestViolations prefixes the fields of a ValidationError with the parent
+// field, e.g. "delivery_address.postal_code". A violation without a field
+// refers to the parent itself. Other errors pass through.
+func NestViolations(parent string, err error) error {
+	var validationErr *ValidationError
+	if !errors.As(err, &validationErr) {
+		return err
+	}
+
+	nested := &ValidationError{Violations: make([]FieldViolation, len(validationErr.Violations))}
+	for i, violation := range validationErr.Violations {
+		field := parent
+		if violation.Field != "" {
+			field += "." + violation.Field
+		}
+		nested.Violations[i] = FieldViolation{Field: field, Err: violation.Err}
+	}
+	return nested
+}
+
+type violations []FieldViolation
+
+func (v *violations) check(ok bool, field string, err error) {
+	if !ok {
+		*v = append(*v, FieldViolation{Field: field, Err: err})
+	}
+}
+
+func (v violations) err() error {
+	if len(v) == 0 {
+		return nil
+	}
+	return &ValidationError{Violations: v}
+}
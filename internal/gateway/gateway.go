@@ -0,0 +1,162 @@
+package gateway
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/hsibAD/order-service/internal/grpcstream"
+	"github.com/hsibAD/order-service/internal/logging"
+	pb "github.com/hsibAD/order-service/proto"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// Gateway serves the REST/JSON API described by the google.api.http
+// annotations in order.proto. Calls are translated to gRPC and handed to an
+// in-process server over an in-memory connection, so they pass the same
+// interceptors (logging, metrics, auth, rate limiting) as native gRPC calls.
+type Gateway struct {
+	server   *grpc.Server
+	listener *bufconn.Listener
+	conn     *grpc.ClientConn
+	http     *http.Server
+}
+
+// New builds a gateway listening on addr for the services registered on
+// server, which must not be served elsewhere. It serves HTTPS when tlsConfig
+// is set.
+func New(ctx context.Context, addr string, tlsConfig *tls.Config, server *grpc.Server) (*Gateway, error) {
+	listener := bufconn.Listen(1 << 20)
+	conn, err := grpc.NewClient("passthrough:///gateway",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler()),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect gateway: %v", err)
+	}
+
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true, EmitUnpopulated: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(writeError),
+		runtime.WithIncomingHeaderMatcher(matchHeader),
+	)
+	if err := pb.RegisterOrderServiceHandler(ctx, mux, conn); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to register gateway handlers: %v", err)
+	}
+
+	httpMux := http.NewServeMux()
+	httpMux.Handle("/", mux)
+	httpMux.HandleFunc("/openapi.json", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(pb.OpenAPI)
+	})
+
+	return &Gateway{
+		server:   server,
+		listener: listener,
+		conn:     conn,
+		http: &http.Server{
+			Addr:              addr,
+			Handler:           httpMux,
+			TLSConfig:         tlsConfig,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}, nil
+}
+
+func (g *Gateway) Addr() string {
+	return g.http.Addr
+}
+
+// Serve blocks until the gateway fails or is shut down, returning nil in
+// the latter case.
+func (g *Gateway) Serve() error {
+	go g.server.Serve(g.listener)
+
+	var err error
+	if g.http.TLSConfig != nil {
+		// The certificate comes from TLSConfig
+		err = g.http.ListenAndServeTLS("", "")
+	} else {
+		err = g.http.ListenAndServe()
+	}
+	if err == http.ErrServerClosed {
+		return nil
+	}
+	return err
+}
+
+// Shutdown stops accepting REST calls and waits for in-flight ones until
+// ctx is done, then cancels them.
+func (g *Gateway) Shutdown(ctx context.Context) error {
+	err := g.http.Shutdown(ctx)
+
+	stopped := make(chan struct{})
+	go func() {
+		g.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		g.server.Stop()
+	}
+
+	g.conn.Close()
+	return err
+}
+
+// matchHeader forwards the request ID on top of the headers the gateway
+// passes by default, such as Authorization.
+func matchHeader(key string) (string, bool) {
+	if strings.EqualFold(key, logging.RequestIDHeader) {
+		return logging.RequestIDHeader, true
+	}
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// UnaryServerInterceptor restores the address of the REST client, passed by
+// the gateway as the last X-Forwarded-For entry, as the peer of the call. It
+// must only be installed on the gateway's in-process server, where the
+// header can't come from anyone else.
+func UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		return handler(forwardedPeer(ctx), req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls.
+func StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return handler(srv, grpcstream.WithContext(ss, forwardedPeer(ss.Context())))
+	}
+}
+
+func forwardedPeer(ctx context.Context) context.Context {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("x-forwarded-for"); len(values) > 0 {
+		forwarded := strings.Split(values[len(values)-1], ",")
+		if ip := net.ParseIP(strings.TrimSpace(forwarded[len(forwarded)-1])); ip != nil {
+			return peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: ip}})
+		}
+	}
+	return ctx
+}
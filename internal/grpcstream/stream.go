@@ -0,0 +1,26 @@
+// Package grpcstream lets stream interceptors hand a derived context to the
+// handler, as unary interceptors do by passing it on.
+package grpcstream
+
+import (
+	"context"
+
+	"google.golang.org/grpc"
+)
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// WithContext returns ss with ctx as its context.
+func WithContext(ss grpc.ServerStream, ctx context.Context) grpc.ServerStream {
+	if ctx == ss.Context() {
+		return ss
+	}
+	return &serverStream{ServerStream: ss, ctx: ctx}
+}
@@ -0,0 +1,117 @@
+package handler
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"github.com/hsibAD/order-service/internal/usecase"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+)
+
+// errorDomain identifies this service in ErrorInfo details.
+const errorDomain = "order-service"
+
+type errorMapping struct {
+	err    error
+	code   codes.Code
+	reason string // Stable machine-readable reason for ErrorInfo
+}
+
+// errorMappings lists every domain error clients can act on. Anything not
+// listed becomes Internal.
+var errorMappings = []errorMapping{
+	{domain.ErrInvalidUserID, codes.InvalidArgument, "INVALID_USER_ID"},
+	{domain.ErrEmptyItems, codes.InvalidArgument, "EMPTY_ITEMS"},
+	{domain.ErrInvalidTotalPrice, codes.InvalidArgument, "INVALID_TOTAL_PRICE"},
+	{domain.ErrInvalidDeliveryTime, codes.InvalidArgument, "INVALID_DELIVERY_TIME"},
+	{domain.ErrInvalidCourierID, codes.InvalidArgument, "INVALID_COURIER_ID"},
+	{domain.ErrInvalidSlotID, codes.InvalidArgument, "INVALID_SLOT_ID"},
+	{domain.ErrInvalidFullName, codes.InvalidArgument, "INVALID_FULL_NAME"},
+	{domain.ErrInvalidStreetAddress, codes.InvalidArgument, "INVALID_STREET_ADDRESS"},
+	{domain.ErrInvalidCity, codes.InvalidArgument, "INVALID_CITY"},
+	{domain.ErrInvalidState, codes.InvalidArgument, "INVALID_STATE"},
+	{domain.ErrInvalidPostalCode, codes.InvalidArgument, "INVALID_POSTAL_CODE"},
+	{domain.ErrInvalidCountry, codes.InvalidArgument, "INVALID_COUNTRY"},
+	{domain.ErrInvalidPhone, codes.InvalidArgument, "INVALID_PHONE"},
+
+	{domain.ErrBelowMinimumOrder, codes.FailedPrecondition, "BELOW_MINIMUM_ORDER"},
+	{domain.ErrAddressNotServiceable, codes.FailedPrecondition, "ADDRESS_NOT_SERVICEABLE"},
+	{domain.ErrSlotUnavailable, codes.FailedPrecondition, "SLOT_UNAVAILABLE"},
+	{domain.ErrInvalidHoldToken, codes.FailedPrecondition, "INVALID_HOLD_TOKEN"},
+	{domain.ErrHoldSlotMismatch, codes.FailedPrecondition, "HOLD_SLOT_MISMATCH"},
+	{domain.ErrOrderClosed, codes.FailedPrecondition, "ORDER_CLOSED"},
+
+	{domain.ErrStaleLock, codes.Aborted, "STALE_LOCK"},
+
+	{domain.ErrInvalidOrderID, codes.NotFound, "ORDER_NOT_FOUND"},
+	{domain.ErrInvalidAddressID, codes.NotFound, "ADDRESS_NOT_FOUND"},
+
+	{usecase.ErrWatchStopped, codes.Unavailable, "WATCH_STOPPED"},
+}
+
+// toStatusError converts domain errors into gRPC status errors. Validation
+// errors carry a BadRequest detail with every field violation, and known
+// errors an ErrorInfo with a stable reason.
+func toStatusError(ctx context.Context, err error, action string) error {
+	var validationErr *domain.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationStatus(validationErr).Err()
+	}
+
+	if mapping, ok := lookupError(err); ok {
+		return withDetails(status.New(mapping.code, err.Error()), &errdetails.ErrorInfo{
+			Reason: mapping.reason,
+			Domain: errorDomain,
+		}).Err()
+	}
+
+	// Driver and publisher errors can quote the document or payload they
+	// failed on, so the details stay in the server log, redacted.
+	logging.FromContext(ctx).Error("failed to "+action, zap.String("error", domain.RedactText(err.Error())))
+	return status.Errorf(codes.Internal, "failed to %s", action)
+}
+
+func validationStatus(err *domain.ValidationError) *status.Status {
+	badRequest := &errdetails.BadRequest{}
+	// Per-field reasons, keyed by field, for clients that localize messages
+	reasons := make(map[string]string, len(err.Violations))
+	for _, violation := range err.Violations {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Err.Error(),
+		})
+		if mapping, ok := lookupError(violation.Err); ok {
+			reasons[violation.Field] = mapping.reason
+		}
+	}
+
+	return withDetails(status.New(codes.InvalidArgument, err.Error()), badRequest, &errdetails.ErrorInfo{
+		Reason:   "VALIDATION_FAILED",
+		Domain:   errorDomain,
+		Metadata: reasons,
+	})
+}
+
+func lookupError(err error) (errorMapping, bool) {
+	for _, mapping := range errorMappings {
+		if errors.Is(err, mapping.err) {
+			return mapping, true
+		}
+	}
+	return errorMapping{}, false
+}
+
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) *status.Status {
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		// Details only fail to marshal on programming errors; keep the code
+		return st
+	}
+	return detailed
+}
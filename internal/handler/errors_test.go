@@ -0,0 +1,82 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	testPhone  = "+44 20 7946 0958"
+	testStreet = "221B Baker Street"
+)
+
+func bufferContext() (context.Context, *bytes.Buffer) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zap.DebugLevel)
+	return logging.NewContext(context.Background(), zap.New(core)), &buf
+}
+
+func TestInternalErrorsHidePersonalData(t *testing.T) {
+	ctx, logs := bufferContext()
+	cause := errors.New(`write exception: duplicate key {phone: "` + testPhone + `", street_address: "` + testStreet + `"}`)
+
+	err := toStatusError(ctx, cause, "create order")
+
+	st := status.Convert(err)
+	if st.Code() != codes.Internal {
+		t.Fatalf("code = %v, want Internal", st.Code())
+	}
+	if strings.Contains(st.Message(), testPhone) || strings.Contains(st.Message(), testStreet) {
+		t.Errorf("status message leaks the cause: %q", st.Message())
+	}
+	if strings.Contains(logs.String(), testPhone) {
+		t.Errorf("log contains the phone: %s", logs.String())
+	}
+	if !strings.Contains(logs.String(), "[phone]") {
+		t.Errorf("log lost the redacted cause: %s", logs.String())
+	}
+}
+
+func TestValidationErrorsHidePersonalData(t *testing.T) {
+	ctx, logs := bufferContext()
+	address := &domain.DeliveryAddress{
+		UserID:        "user-1",
+		FullName:      "Jane Roe",
+		StreetAddress: testStreet,
+		City:          "London",
+		PostalCode:    "not a postcode",
+		Country:       "GB",
+		Phone:         "call " + testPhone + " please",
+	}
+
+	err := toStatusError(ctx, address.Validate(), "add delivery address")
+
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %v, want InvalidArgument", st.Code())
+	}
+	text := st.Message()
+	for _, detail := range st.Details() {
+		if d, ok := detail.(interface{ String() string }); ok {
+			text += d.String()
+		}
+	}
+	for _, value := range []string{testPhone, testStreet, "Jane Roe"} {
+		if strings.Contains(text, value) {
+			t.Errorf("status contains %q: %s", value, text)
+		}
+	}
+	if logs.Len() != 0 {
+		t.Errorf("validation errors are logged: %s", logs.String())
+	}
+}
@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/usecase"
+	pb "github.com/hsibAD/order-service/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toProtoOrder(order *domain.Order) *pb.Order {
+	items := make([]*pb.OrderItem, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = &pb.OrderItem{
+			ProductId:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TotalPrice:  item.TotalPrice,
+		}
+	}
+
+	return &pb.Order{
+		Id:              order.ID,
+		UserId:          order.UserID,
+		Items:           items,
+		DeliveryFee:     order.DeliveryFee,
+		TotalPrice:      order.TotalPrice,
+		Currency:        order.Currency,
+		Status:          string(order.Status),
+		DeliveryAddress: toProtoDeliveryAddress(order.DeliveryAddress),
+		DeliveryTime:    timestamppb.New(order.DeliveryTime),
+		CourierId:       order.CourierID,
+		CreatedAt:       timestamppb.New(order.CreatedAt),
+		UpdatedAt:       timestamppb.New(order.UpdatedAt),
+	}
+}
+
+func toProtoDeliveryAddress(address *domain.DeliveryAddress) *pb.DeliveryAddress {
+	if address == nil {
+		return nil
+	}
+
+	return &pb.DeliveryAddress{
+		Id:         address.ID,
+		UserId:     address.UserID,
+		Street:     address.StreetAddress,
+		City:       address.City,
+		State:      address.State,
+		Country:    address.Country,
+		PostalCode: address.PostalCode,
+		FullName:   address.FullName,
+		Apartment:  address.Apartment,
+		Phone:      address.Phone,
+		IsDefault:  address.IsDefault,
+		Location:   toProtoGeoPoint(address.Location),
+		Preferences: &pb.DeliveryPreferences{
+			Instructions:  address.Preferences.Instructions,
+			LeaveAtDoor:   address.Preferences.LeaveAtDoor,
+			Floor:         address.Preferences.Floor,
+			HasElevator:   address.Preferences.HasElevator,
+			ContactMethod: string(address.Preferences.ContactMethod),
+			AccessCode:    address.Preferences.AccessCode,
+		},
+	}
+}
+
+func fromProtoDeliveryPreferences(preferences *pb.DeliveryPreferences) domain.DeliveryPreferences {
+	return domain.DeliveryPreferences{
+		Instructions:  preferences.GetInstructions(),
+		LeaveAtDoor:   preferences.GetLeaveAtDoor(),
+		Floor:         preferences.GetFloor(),
+		HasElevator:   preferences.GetHasElevator(),
+		ContactMethod: domain.ContactMethod(preferences.GetContactMethod()),
+		AccessCode:    preferences.GetAccessCode(),
+	}
+}
+
+func toProtoGeoPoint(point *domain.GeoPoint) *pb.GeoPoint {
+	if point == nil {
+		return nil
+	}
+	return &pb.GeoPoint{Latitude: point.Latitude, Longitude: point.Longitude}
+}
+
+func fromProtoGeoPoint(point *pb.GeoPoint) *domain.GeoPoint {
+	if point == nil {
+		return nil
+	}
+	return &domain.GeoPoint{Latitude: point.GetLatitude(), Longitude: point.GetLongitude()}
+}
+
+// toProtoUserDataExport keeps access codes, as exports hand users all the data
+// we hold about them.
+func toProtoUserDataExport(export *usecase.UserDataExport) *pb.UserDataExport {
+	orders := make([]*pb.Order, len(export.Orders))
+	for i, order := range export.Orders {
+		orders[i] = toProtoOrder(order)
+	}
+
+	addresses := make([]*pb.DeliveryAddress, len(export.Addresses))
+	for i, address := range export.Addresses {
+		addresses[i] = toProtoDeliveryAddress(address)
+	}
+
+	return &pb.UserDataExport{
+		UserId:     export.UserID,
+		Orders:     orders,
+		Addresses:  addresses,
+		ExportedAt: timestamppb.New(export.ExportedAt),
+	}
+}
+
+func toProtoDeliverySlot(slot *domain.DeliverySlot) *pb.DeliverySlot {
+	return &pb.DeliverySlot{
+		Id:              slot.ID,
+		StartTime:       timestamppb.New(slot.StartTime),
+		EndTime:         timestamppb.New(slot.EndTime),
+		Available:       slot.Available,
+		DeliveryFee:     slot.DeliveryFee,
+		SurgeMultiplier: slot.SurgeMultiplier,
+	}
+}
+
+func toProtoDeliverySlotHold(hold *domain.SlotHold) *pb.DeliverySlotHold {
+	return &pb.DeliverySlotHold{
+		Token:     hold.Token,
+		SlotId:    hold.SlotID,
+		ExpiresAt: timestamppb.New(hold.ExpiresAt),
+	}
+}
+
+// fromProtoOrderItems recomputes line totals rather than trusting the client.
+func fromProtoOrderItems(items []*pb.OrderItem) []domain.OrderItem {
+	result := make([]domain.OrderItem, len(items))
+	for i, item := range items {
+		result[i] = domain.OrderItem{
+			ProductID:   item.GetProductId(),
+			ProductName: item.GetProductName(),
+			Quantity:    item.GetQuantity(),
+			UnitPrice:   item.GetUnitPrice(),
+			TotalPrice:  item.GetUnitPrice() * float64(item.GetQuantity()),
+		}
+	}
+	return result
+}
+
+func fromProtoDeliveryAddress(userID string, address *pb.DeliveryAddress) (*domain.DeliveryAddress, error) {
+	if address == nil {
+		return nil, &domain.ValidationError{Violations: []domain.FieldViolation{
+			{Err: domain.ErrInvalidStreetAddress},
+		}}
+	}
+
+	result, err := domain.NewDeliveryAddress(
+		userID,
+		address.GetFullName(),
+		address.GetStreet(),
+		address.GetApartment(),
+		address.GetCity(),
+		address.GetState(),
+		address.GetPostalCode(),
+		address.GetCountry(),
+		address.GetPhone(),
+		address.GetIsDefault(),
+		fromProtoDeliveryPreferences(address.GetPreferences()),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	result.ID = address.GetId()
+	return result, nil
+}
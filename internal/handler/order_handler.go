@@ -1,49 +1,303 @@
-package handler
-
-import (
-	"context"
-
-	"github.com/hsibAD/order-service/internal/domain"
-	pb "github.com/hsibAD/order-service/proto"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
-)
-
-type OrderHandler struct {
-	pb.UnimplementedOrderServiceServer
-}
-
-func RegisterServices(s *grpc.Server, cfg interface{}) {
-	pb.RegisterOrderServiceServer(s, &OrderHandler{})
-}
-
-func (h *OrderHandler) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.Order, error) {
-	// TODO: Implement order creation logic
-	return nil, status.Error(codes.Unimplemented, "method CreateOrder not implemented")
-}
-
-func (h *OrderHandler) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
-	// TODO: Implement get order logic
-	return nil, status.Error(codes.Unimplemented, "method GetOrder not implemented")
-}
-
-func (h *OrderHandler) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrderStatusRequest) (*pb.Order, error) {
-	// TODO: Implement update order status logic
-	return nil, status.Error(codes.Unimplemented, "method UpdateOrderStatus not implemented")
-}
-
-func (h *OrderHandler) AddDeliveryAddress(ctx context.Context, req *pb.DeliveryAddress) (*pb.DeliveryAddress, error) {
-	// TODO: Implement add delivery address logic
-	return nil, status.Error(codes.Unimplemented, "method AddDeliveryAddress not implemented")
-}
-
-func (h *OrderHandler) ListDeliveryAddresses(ctx context.Context, req *pb.ListAddressesRequest) (*pb.ListAddressesResponse, error) {
-	// TODO: Implement list delivery addresses logic
-	return nil, status.Error(codes.Unimplemented, "method ListDeliveryAddresses not implemented")
-}
-
-func (h *OrderHandler) GetAvailableDeliverySlots(ctx context.Context, req *pb.DeliverySlotsRequest) (*pb.DeliverySlotsResponse, error) {
-	// TODO: Implement get available delivery slots logic
-	return nil, status.Error(codes.Unimplemented, "method GetAvailableDeliverySlots not implemented")
-} 
\ No newline at end of file
+package handler
+
+import (
+	"context"
+
+	"github.com/hsibAD/order-service/internal/auth"
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/usecase"
+	pb "github.com/hsibAD/order-service/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// duplicateAddressHeader is set on AddDeliveryAddress responses that returned
+// an already saved address instead of creating one.
+const duplicateAddressHeader = "x-duplicate-address-id"
+
+type OrderHandler struct {
+	pb.UnimplementedOrderServiceServer
+	orders    *usecase.OrderService
+	addresses *usecase.AddressService
+	slots     *usecase.SlotService
+	privacy   *usecase.PrivacyService
+}
+
+func NewOrderHandler(
+	orders *usecase.OrderService,
+	addresses *usecase.AddressService,
+	slots *usecase.SlotService,
+	privacy *usecase.PrivacyService,
+) *OrderHandler {
+	return &OrderHandler{
+		orders:    orders,
+		addresses: addresses,
+		slots:     slots,
+		privacy:   privacy,
+	}
+}
+
+func RegisterServices(s *grpc.Server, h *OrderHandler) {
+	pb.RegisterOrderServiceServer(s, h)
+}
+
+func (h *OrderHandler) CreateOrder(ctx context.Context, req *pb.CreateOrderRequest) (*pb.Order, error) {
+	if err := authorizeUser(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	address, err := fromProtoDeliveryAddress(req.GetUserId(), req.GetDeliveryAddress())
+	if err != nil {
+		return nil, toStatusError(ctx, domain.NestViolations("delivery_address", err), "create order")
+	}
+
+	input := usecase.CreateOrderInput{
+		UserID:          req.GetUserId(),
+		Items:           fromProtoOrderItems(req.GetItems()),
+		DeliveryAddress: address,
+		HoldToken:       req.GetHoldToken(),
+	}
+	if req.GetDeliveryTime() != nil {
+		input.DeliveryTime = req.GetDeliveryTime().AsTime()
+	}
+
+	order, err := h.orders.CreateOrder(ctx, input)
+	if err != nil {
+		return nil, toStatusError(ctx, err, "create order")
+	}
+
+	return toProtoOrder(orderForCaller(ctx, order)), nil
+}
+
+func (h *OrderHandler) GetOrder(ctx context.Context, req *pb.GetOrderRequest) (*pb.Order, error) {
+	// Anonymous callers can't tell which orders exist
+	if _, err := requireCaller(ctx); err != nil {
+		return nil, err
+	}
+
+	order, err := h.orders.GetOrder(ctx, req.GetOrderId())
+	if err != nil {
+		return nil, toStatusError(ctx, err, "get order")
+	}
+	if err := authorizeOrder(ctx, order); err != nil {
+		return nil, err
+	}
+
+	return toProtoOrder(orderForCaller(ctx, order)), nil
+}
+
+// AssignCourier is for admins and the dispatch service.
+func (h *OrderHandler) AssignCourier(ctx context.Context, req *pb.AssignCourierRequest) (*pb.Order, error) {
+	principal, err := requireCaller(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !principal.HasRole(auth.RoleAdmin) {
+		return nil, status.Error(codes.PermissionDenied, "not allowed to assign couriers")
+	}
+
+	order, err := h.orders.AssignCourier(ctx, req.GetOrderId(), req.GetCourierId())
+	if err != nil {
+		return nil, toStatusError(ctx, err, "assign courier")
+	}
+
+	return toProtoOrder(orderForCaller(ctx, order)), nil
+}
+
+// WatchOrder streams the order to its owner, its courier and admins as it
+// changes, see usecase.OrderService.WatchOrder.
+func (h *OrderHandler) WatchOrder(req *pb.WatchOrderRequest, stream pb.OrderService_WatchOrderServer) error {
+	ctx := stream.Context()
+	if _, err := requireCaller(ctx); err != nil {
+		return err
+	}
+
+	order, err := h.orders.GetOrder(ctx, req.GetOrderId())
+	if err != nil {
+		return toStatusError(ctx, err, "watch order")
+	}
+	if err := authorizeOrder(ctx, order); err != nil {
+		return err
+	}
+
+	err = h.orders.WatchOrder(ctx, order.ID, req.GetSinceVersion(), func(update usecase.OrderUpdate) error {
+		msg := &pb.OrderUpdate{
+			Version: update.Version,
+			Event:   update.Event,
+			SentAt:  timestamppb.Now(),
+		}
+		if update.Order != nil {
+			msg.Order = toProtoOrder(orderForCaller(ctx, update.Order))
+		}
+		return stream.Send(msg)
+	})
+	if err == nil {
+		return nil
+	}
+	if ctx.Err() != nil {
+		// The client went away
+		return status.FromContextError(ctx.Err()).Err()
+	}
+	if _, ok := status.FromError(err); ok {
+		// Sends fail with the status of the broken stream
+		return err
+	}
+	return toStatusError(ctx, err, "watch order")
+}
+
+func (h *OrderHandler) UpdateOrderStatus(ctx context.Context, req *pb.UpdateOrderStatusRequest) (*pb.Order, error) {
+	// TODO: Implement update order status logic
+	return nil, status.Error(codes.Unimplemented, "method UpdateOrderStatus not implemented")
+}
+
+func (h *OrderHandler) AddDeliveryAddress(ctx context.Context, req *pb.DeliveryAddress) (*pb.DeliveryAddress, error) {
+	if err := authorizeUser(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	address, err := fromProtoDeliveryAddress(req.GetUserId(), req)
+	if err != nil {
+		return nil, toStatusError(ctx, err, "add delivery address")
+	}
+
+	address, duplicate, err := h.addresses.AddAddress(ctx, address)
+	if err != nil {
+		return nil, toStatusError(ctx, err, "add delivery address")
+	}
+
+	if duplicate {
+		// Lets clients tell the user the address was already saved
+		grpc.SetHeader(ctx, metadata.Pairs(duplicateAddressHeader, address.ID))
+	}
+
+	// Saved addresses have no courier, so their access code is never shown
+	return toProtoDeliveryAddress(address.WithoutAccessCode()), nil
+}
+
+func (h *OrderHandler) ListDeliveryAddresses(ctx context.Context, req *pb.ListAddressesRequest) (*pb.ListAddressesResponse, error) {
+	// TODO: Implement list delivery addresses logic
+	return nil, status.Error(codes.Unimplemented, "method ListDeliveryAddresses not implemented")
+}
+
+func (h *OrderHandler) CheckServiceability(ctx context.Context, req *pb.CheckServiceabilityRequest) (*pb.ServiceabilityResponse, error) {
+	result := h.addresses.CheckServiceability(req.GetPostalCode(), req.GetCountry(), fromProtoGeoPoint(req.GetLocation()))
+	return &pb.ServiceabilityResponse{
+		Serviceable: result.Serviceable,
+		ZoneId:      result.ZoneID,
+		Reason:      result.Reason,
+	}, nil
+}
+
+func (h *OrderHandler) GetAvailableDeliverySlots(ctx context.Context, req *pb.DeliverySlotsRequest) (*pb.DeliverySlotsResponse, error) {
+	if req.GetDate() == nil {
+		return nil, status.Error(codes.InvalidArgument, "date is required")
+	}
+
+	slots, err := h.slots.GetAvailableSlots(ctx, req.GetDate().AsTime())
+	if err != nil {
+		return nil, toStatusError(ctx, err, "get delivery slots")
+	}
+
+	pricing := h.slots.Pricing()
+	resp := &pb.DeliverySlotsResponse{
+		Slots:                 make([]*pb.DeliverySlot, len(slots)),
+		FreeDeliveryThreshold: pricing.FreeDeliveryThresholdFor(req.GetPostalCode()),
+		MinimumOrderTotal:     pricing.MinimumOrderTotal,
+	}
+	for i, slot := range slots {
+		resp.Slots[i] = toProtoDeliverySlot(slot)
+	}
+
+	return resp, nil
+}
+
+func (h *OrderHandler) HoldDeliverySlot(ctx context.Context, req *pb.HoldDeliverySlotRequest) (*pb.DeliverySlotHold, error) {
+	if err := authorizeUser(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	hold, err := h.slots.HoldSlot(ctx, req.GetUserId(), req.GetSlotId())
+	if err != nil {
+		return nil, toStatusError(ctx, err, "hold delivery slot")
+	}
+
+	return toProtoDeliverySlotHold(hold), nil
+}
+
+func (h *OrderHandler) ExportUserData(ctx context.Context, req *pb.ExportUserDataRequest) (*pb.UserDataExport, error) {
+	if err := authorizeUser(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	export, err := h.privacy.ExportUserData(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatusError(ctx, err, "export user data")
+	}
+
+	return toProtoUserDataExport(export), nil
+}
+
+func (h *OrderHandler) EraseUserData(ctx context.Context, req *pb.EraseUserDataRequest) (*pb.EraseUserDataResponse, error) {
+	if err := authorizeUser(ctx, req.GetUserId()); err != nil {
+		return nil, err
+	}
+
+	result, err := h.privacy.EraseUserData(ctx, req.GetUserId())
+	if err != nil {
+		return nil, toStatusError(ctx, err, "erase user data")
+	}
+
+	return &pb.EraseUserDataResponse{
+		OrdersPseudonymized: int32(result.OrdersPseudonymized),
+		AddressesDeleted:    int32(result.AddressesDeleted),
+	}, nil
+}
+
+func requireCaller(ctx context.Context) (*auth.Principal, error) {
+	principal := auth.FromContext(ctx)
+	if principal == nil {
+		return nil, status.Error(codes.Unauthenticated, "authentication required")
+	}
+	return principal, nil
+}
+
+// authorizeUser admits userID themselves and admins.
+func authorizeUser(ctx context.Context, userID string) error {
+	principal, err := requireCaller(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.UserID != userID && !principal.HasRole(auth.RoleAdmin) {
+		return status.Error(codes.PermissionDenied, "not allowed to access this user's data")
+	}
+	return nil
+}
+
+// authorizeOrder admits the order's owner, its courier and admins.
+func authorizeOrder(ctx context.Context, order *domain.Order) error {
+	principal, err := requireCaller(ctx)
+	if err != nil {
+		return err
+	}
+	if principal.UserID != order.UserID && !isAssignedCourier(principal, order) && !principal.HasRole(auth.RoleAdmin) {
+		return status.Error(codes.PermissionDenied, "not allowed to access this order")
+	}
+	return nil
+}
+
+func isAssignedCourier(principal *auth.Principal, order *domain.Order) bool {
+	return principal != nil && principal.HasRole(auth.RoleCourier) &&
+		order.CourierID != "" && principal.UserID == order.CourierID
+}
+
+// orderForCaller hides the access code from everyone but the courier
+// assigned to the order.
+func orderForCaller(ctx context.Context, order *domain.Order) *domain.Order {
+	if isAssignedCourier(auth.FromContext(ctx), order) {
+		return order
+	}
+	return order.WithoutAccessCode()
+}
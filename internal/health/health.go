@@ -0,0 +1,213 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// LivenessService is the grpc.health.v1 service name for liveness probes.
+// The empty name and the service names passed to NewMonitor report
+// readiness.
+const LivenessService = "liveness"
+
+// Checker reports whether one dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Monitor runs the dependency checks in the background and publishes the
+// outcome through the grpc.health.v1 service and HTTP probe handlers.
+//
+// Liveness only says the process is up and answering; a dependency outage
+// must not get the pod restarted. Readiness requires every check to pass,
+// and goes NOT_SERVING for good once Shutdown is called.
+type Monitor struct {
+	server   *health.Server
+	services []string
+	timeout  time.Duration
+	checkers []namedChecker
+
+	mu           sync.RWMutex
+	results      map[string]error
+	checked      bool
+	shuttingDown bool
+}
+
+// NewMonitor creates a monitor reporting readiness for services. Each check
+// gets timeout to complete.
+func NewMonitor(timeout time.Duration, services ...string) *Monitor {
+	m := &Monitor{
+		server:   health.NewServer(),
+		services: append([]string{""}, services...),
+		timeout:  timeout,
+		results:  make(map[string]error),
+	}
+	m.server.SetServingStatus(LivenessService, healthpb.HealthCheckResponse_SERVING)
+	m.setReadiness(false)
+	return m
+}
+
+// Register adds a dependency check. Call before Run.
+func (m *Monitor) Register(name string, checker Checker) {
+	m.checkers = append(m.checkers, namedChecker{name: name, checker: checker})
+}
+
+// Server is the grpc.health.v1 implementation to register.
+func (m *Monitor) Server() healthpb.HealthServer {
+	return m.server
+}
+
+// Run checks the dependencies now and then every interval until ctx is done.
+func (m *Monitor) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		m.CheckNow(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// CheckNow runs every check concurrently and updates the serving status.
+func (m *Monitor) CheckNow(ctx context.Context) {
+	results := make(map[string]error, len(m.checkers))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, c := range m.checkers {
+		wg.Add(1)
+		go func(c namedChecker) {
+			defer wg.Done()
+			checkCtx, cancel := context.WithTimeout(ctx, m.timeout)
+			defer cancel()
+
+			err := c.checker.Check(checkCtx)
+			mu.Lock()
+			results[c.name] = err
+			mu.Unlock()
+		}(c)
+	}
+	wg.Wait()
+
+	m.mu.Lock()
+	for name, err := range results {
+		previous, seen := m.results[name]
+		if err != nil && (!seen || previous == nil) {
+			logging.FromContext(ctx).Warn("dependency check failing", zap.String("dependency", name), zap.Error(err))
+		} else if err == nil && seen && previous != nil {
+			logging.FromContext(ctx).Info("dependency check recovered", zap.String("dependency", name))
+		}
+	}
+	m.results = results
+	m.checked = true
+	ready := m.readyLocked()
+	m.mu.Unlock()
+
+	m.setReadiness(ready)
+}
+
+// Shutdown marks the service not ready, so load balancers stop sending new
+// calls while in-flight ones finish.
+func (m *Monitor) Shutdown() {
+	m.mu.Lock()
+	m.shuttingDown = true
+	m.mu.Unlock()
+
+	m.setReadiness(false)
+}
+
+// Ready reports readiness and the last error of each check.
+func (m *Monitor) Ready() (bool, map[string]error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	results := make(map[string]error, len(m.results))
+	for name, err := range m.results {
+		results[name] = err
+	}
+	return m.readyLocked(), results
+}
+
+func (m *Monitor) readyLocked() bool {
+	if m.shuttingDown || !m.checked {
+		return false
+	}
+	for _, err := range m.results {
+		if err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (m *Monitor) setReadiness(ready bool) {
+	status := healthpb.HealthCheckResponse_NOT_SERVING
+	if ready {
+		status = healthpb.HealthCheckResponse_SERVING
+	}
+	for _, service := range m.services {
+		m.server.SetServingStatus(service, status)
+	}
+}
+
+// LivenessHandler serves /healthz.
+func (m *Monitor) LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeStatus(w, http.StatusOK, "ok", nil)
+	})
+}
+
+// ReadinessHandler serves /readyz with the state of each check.
+func (m *Monitor) ReadinessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ready, results := m.Ready()
+
+		checks := make(map[string]string, len(results))
+		for name, err := range results {
+			checks[name] = "ok"
+			if err != nil {
+				checks[name] = err.Error()
+			}
+		}
+
+		if ready {
+			writeStatus(w, http.StatusOK, "ok", checks)
+		} else {
+			writeStatus(w, http.StatusServiceUnavailable, "unavailable", checks)
+		}
+	})
+}
+
+func writeStatus(w http.ResponseWriter, code int, status string, checks map[string]string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(struct {
+		Status string            `json:"status"`
+		Checks map[string]string `json:"checks,omitempty"`
+	}{status, checks})
+}
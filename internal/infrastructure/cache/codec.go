@@ -0,0 +1,56 @@
+package cache
+
+import (
+	"encoding/json"
+
+	"github.com/vmihailenco/msgpack/v5"
+	"google.golang.org/protobuf/proto"
+)
+
+// Codec converts cached values to and from their stored bytes.
+type Codec[T any] interface {
+	Marshal(value T) ([]byte, error)
+	Unmarshal(data []byte) (T, error)
+}
+
+type JSONCodec[T any] struct{}
+
+func (JSONCodec[T]) Marshal(value T) ([]byte, error) {
+	return json.Marshal(value)
+}
+
+func (JSONCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
+
+type MsgpackCodec[T any] struct{}
+
+func (MsgpackCodec[T]) Marshal(value T) ([]byte, error) {
+	return msgpack.Marshal(value)
+}
+
+func (MsgpackCodec[T]) Unmarshal(data []byte) (T, error) {
+	var value T
+	err := msgpack.Unmarshal(data, &value)
+	return value, err
+}
+
+// ProtoCodec stores generated protobuf messages, e.g. ProtoCodec[*pb.Order].
+type ProtoCodec[T proto.Message] struct{}
+
+func (ProtoCodec[T]) Marshal(value T) ([]byte, error) {
+	return proto.Marshal(value)
+}
+
+func (ProtoCodec[T]) Unmarshal(data []byte) (T, error) {
+	// Generated messages answer ProtoReflect on a nil receiver, which is
+	// enough to allocate a fresh instance of T
+	var zero T
+	value := zero.ProtoReflect().New().Interface().(T)
+	if err := proto.Unmarshal(data, value); err != nil {
+		return zero, err
+	}
+	return value, nil
+}
@@ -0,0 +1,87 @@
+package cache
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/hsibAD/order-service/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type codecValue struct {
+	ID     string            `json:"id" msgpack:"id"`
+	Count  int               `json:"count" msgpack:"count"`
+	At     time.Time         `json:"at" msgpack:"at"`
+	Labels map[string]string `json:"labels" msgpack:"labels"`
+}
+
+func TestCodecsRoundTrip(t *testing.T) {
+	value := &codecValue{
+		ID:     "order-1",
+		Count:  3,
+		At:     time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC),
+		Labels: map[string]string{"zone": "NW1"},
+	}
+
+	tests := []struct {
+		name  string
+		codec Codec[*codecValue]
+	}{
+		{"json", JSONCodec[*codecValue]{}},
+		{"msgpack", MsgpackCodec[*codecValue]{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := tt.codec.Marshal(value)
+			if err != nil {
+				t.Fatalf("Marshal: %v", err)
+			}
+			got, err := tt.codec.Unmarshal(data)
+			if err != nil {
+				t.Fatalf("Unmarshal: %v", err)
+			}
+			if !got.At.Equal(value.At) {
+				t.Errorf("at = %v, want %v", got.At, value.At)
+			}
+			got.At = value.At
+			if !reflect.DeepEqual(got, value) {
+				t.Errorf("round trip = %+v, want %+v", got, value)
+			}
+
+			if _, err := tt.codec.Unmarshal([]byte{0xc1}); err == nil {
+				t.Error("Unmarshal accepted invalid data")
+			}
+		})
+	}
+}
+
+func TestProtoCodecRoundTrip(t *testing.T) {
+	codec := ProtoCodec[*pb.Order]{}
+	order := &pb.Order{
+		Id:         "order-1",
+		UserId:     "user-1",
+		Items:      []*pb.OrderItem{{ProductId: "p-1", Quantity: 2, UnitPrice: 4.5}},
+		TotalPrice: 9,
+		Currency:   "GBP",
+		CreatedAt:  timestamppb.New(time.Date(2024, 5, 1, 10, 30, 0, 0, time.UTC)),
+	}
+
+	data, err := codec.Marshal(order)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	got, err := codec.Unmarshal(data)
+	if err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+	if !proto.Equal(got, order) {
+		t.Errorf("round trip = %v, want %v", got, order)
+	}
+
+	if _, err := codec.Unmarshal([]byte{0xff}); err == nil {
+		t.Error("Unmarshal accepted invalid data")
+	}
+}
@@ -0,0 +1,42 @@
+package cache
+
+import (
+	"github.com/hsibAD/order-service/internal/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var tierLookupsDesc = prometheus.NewDesc(
+	prometheus.BuildFQName(metrics.Namespace, "cache", "tier_lookups_total"),
+	"Lookups of the tiered caches by cache, tier (l1 or l2) and result (hit or miss).",
+	[]string{"cache", "tier", "result"}, nil,
+)
+
+// statsCollector exports RedisCache.Stats, read at scrape time.
+type statsCollector struct {
+	cache *RedisCache
+}
+
+// Collector returns a Prometheus collector for the lookups counted by Stats.
+func (c *RedisCache) Collector() prometheus.Collector {
+	return statsCollector{cache: c}
+}
+
+func (s statsCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- tierLookupsDesc
+}
+
+func (s statsCollector) Collect(ch chan<- prometheus.Metric) {
+	for name, stats := range s.cache.Stats() {
+		for _, m := range []struct {
+			tier, result string
+			value        uint64
+		}{
+			{"l1", "hit", stats.L1Hits},
+			{"l1", "miss", stats.L1Misses},
+			{"l2", "hit", stats.L2Hits},
+			{"l2", "miss", stats.L2Misses},
+		} {
+			ch <- prometheus.MustNewConstMetric(tierLookupsDesc, prometheus.CounterValue, float64(m.value), name, m.tier, m.result)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+)
+
+// Delays between subscription attempts, doubling while Redis is unreachable
+const (
+	minResubscribeDelay = 100 * time.Millisecond
+	maxResubscribeDelay = 30 * time.Second
+)
+
+// InvalidationBus broadcasts deleted keys to every replica over Redis
+// pub/sub so in-process tiers can drop their copies.
+type InvalidationBus struct {
+	client *redis.Client
+}
+
+type invalidationMessage struct {
+	Keys []string `json:"keys"`
+}
+
+func NewInvalidationBus(client *redis.Client) *InvalidationBus {
+	return &InvalidationBus{
+		client: client,
+	}
+}
+
+func (b *InvalidationBus) Publish(ctx context.Context, channel string, keys []string) error {
+	data, err := json.Marshal(invalidationMessage{Keys: keys})
+	if err != nil {
+		return err
+	}
+
+	return b.client.Publish(ctx, channel, data).Err()
+}
+
+// Subscribe calls fn for every invalidation on channel until ctx is done.
+// Malformed messages are skipped. Invalidations sent while the subscription
+// is down are lost, so resync is called whenever it is back, and should drop
+// everything that may be stale. go-redis reconnects a broken subscription on
+// its own; one that fails outright is retried with backoff.
+func (b *InvalidationBus) Subscribe(ctx context.Context, channel string, fn func(keys []string), resync func()) {
+	delay := minResubscribeDelay
+	resubscribing := false
+	for {
+		err := b.subscribe(ctx, channel, fn, func() {
+			if resubscribing {
+				resync()
+			}
+			delay = minResubscribeDelay
+		}, resync)
+		if ctx.Err() != nil {
+			return
+		}
+
+		logging.FromContext(ctx).Warn("cache invalidation subscription failed, retrying",
+			zap.String("channel", channel), zap.Duration("delay", delay), zap.Error(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+		delay = min(2*delay, maxResubscribeDelay)
+		resubscribing = true
+	}
+}
+
+// subscribe delivers invalidations until ctx is done or the subscription
+// breaks. subscribed is called once Redis confirmed the subscription, and
+// reconnected every time go-redis restored it after a dropped connection.
+func (b *InvalidationBus) subscribe(ctx context.Context, channel string, fn func(keys []string), subscribed, reconnected func()) error {
+	sub := b.client.Subscribe(ctx, channel)
+	defer sub.Close()
+
+	if _, err := sub.Receive(ctx); err != nil {
+		return err
+	}
+	subscribed()
+
+	// Subscription confirmations after the first come from reconnects
+	messages := sub.ChannelWithSubscriptions(ctx, 100)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case msg, ok := <-messages:
+			if !ok {
+				return errors.New("subscription closed")
+			}
+
+			switch msg := msg.(type) {
+			case *redis.Subscription:
+				if msg.Kind == "subscribe" {
+					logging.FromContext(ctx).Info("cache invalidation subscription restored", zap.String("channel", channel))
+					reconnected()
+				}
+			case *redis.Message:
+				var payload invalidationMessage
+				if err := json.Unmarshal([]byte(msg.Payload), &payload); err != nil {
+					continue
+				}
+				fn(payload.Keys)
+			}
+		}
+	}
+}
@@ -0,0 +1,106 @@
+package cache
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestInvalidationBusResyncsAfterRedisRestart(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	bus := NewInvalidationBus(client)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	invalidated := make(chan []string, 10)
+	resynced := make(chan struct{}, 10)
+	go bus.Subscribe(ctx, "test:invalidate", func(keys []string) {
+		invalidated <- keys
+	}, func() {
+		resynced <- struct{}{}
+	})
+
+	// Publish until the subscription is up
+	waitForInvalidation(t, bus, invalidated)
+	select {
+	case <-resynced:
+		t.Fatal("resync on the first subscription")
+	default:
+	}
+
+	server.Close()
+	if err := server.Restart(); err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-resynced:
+	case <-time.After(10 * time.Second):
+		t.Fatal("no resync after Redis restarted")
+	}
+	waitForInvalidation(t, bus, invalidated)
+}
+
+func waitForInvalidation(t *testing.T, bus *InvalidationBus, invalidated <-chan []string) {
+	t.Helper()
+	deadline := time.After(10 * time.Second)
+	for {
+		_ = bus.Publish(context.Background(), "test:invalidate", []string{"k"})
+		select {
+		case keys := <-invalidated:
+			if len(keys) != 1 || keys[0] != "k" {
+				t.Fatalf("invalidated %q, want [k]", keys)
+			}
+			return
+		case <-time.After(50 * time.Millisecond):
+		case <-deadline:
+			t.Fatal("invalidation not delivered")
+		}
+	}
+}
+
+func TestTieredCacheClearsL1AfterRedisRestart(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	keys := Keyspace{Namespace: "test", Name: "values", Version: 1}
+	l1 := NewMemoryCache[string](10)
+	tiered := NewTieredCache[string](l1, time.Minute,
+		NewRedisTypedCache[string](client, keys, JSONCodec[string]{}),
+		NewInvalidationBus(client), keys.Channel())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go tiered.Listen(ctx)
+
+	// Wait for the listener, then cache a value in both tiers
+	for deadline := time.Now().Add(10 * time.Second); client.PubSubNumSub(ctx, keys.Channel()).Val()[keys.Channel()] == 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("listener did not subscribe")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := tiered.Set(ctx, "k", "v", time.Hour); err != nil {
+		t.Fatal(err)
+	}
+
+	// Invalidations published while the connection is down never arrive
+	server.Close()
+	if err := server.Restart(); err != nil {
+		t.Fatal(err)
+	}
+
+	for deadline := time.Now().Add(10 * time.Second); l1.Len() > 0; {
+		if time.Now().After(deadline) {
+			t.Fatal("L1 kept its entries after Redis restarted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
@@ -0,0 +1,77 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache[int](2)
+
+	_ = c.Set(ctx, "a", 1, 0)
+	_ = c.Set(ctx, "b", 2, 0)
+	// Reading a makes b the least recently used
+	if _, ok, _ := c.Get(ctx, "a"); !ok {
+		t.Fatal("a missing before eviction")
+	}
+	_ = c.Set(ctx, "c", 3, 0)
+
+	if _, ok, _ := c.Get(ctx, "b"); ok {
+		t.Error("b was kept, want it evicted")
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok, _ := c.Get(ctx, key); !ok || got != want {
+			t.Errorf("Get(%s) = %d, %v, want %d, true", key, got, ok, want)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len = %d, want 2", c.Len())
+	}
+}
+
+func TestMemoryCacheExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	c := NewMemoryCache[string](10)
+	c.now = func() time.Time { return now }
+
+	_ = c.Set(ctx, "short", "x", time.Minute)
+	_ = c.Set(ctx, "forever", "y", 0)
+	now = now.Add(time.Minute)
+
+	if _, ok, _ := c.Get(ctx, "short"); ok {
+		t.Error("entry outlived its TTL")
+	}
+	if _, ok, _ := c.Get(ctx, "forever"); !ok {
+		t.Error("entry without a TTL expired")
+	}
+}
+
+func TestMemoryCacheMGetMSet(t *testing.T) {
+	ctx := context.Background()
+	c := NewMemoryCache[string](3)
+
+	_ = c.MSet(ctx, map[string]string{"a": "1", "b": "2"}, 0)
+	got, err := c.MGet(ctx, "a", "b", "missing")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[string]string{"a": "1", "b": "2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MGet = %v, want %v", got, want)
+	}
+
+	// MSet past capacity evicts like Set
+	_ = c.MSet(ctx, map[string]string{"c": "3", "d": "4"}, 0)
+	if c.Len() != 3 {
+		t.Errorf("Len = %d, want 3", c.Len())
+	}
+
+	_ = c.Delete(ctx, "c", "d")
+	got, _ = c.MGet(ctx, "c", "d")
+	if len(got) != 0 {
+		t.Errorf("MGet after Delete = %v, want none", got)
+	}
+}
@@ -0,0 +1,288 @@
+package cache
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// FieldCipher encrypts the personal fields of cached addresses, so Redis
+// holds them no more readable than MongoDB does.
+type FieldCipher interface {
+	Encrypt(plaintext string) (string, error)
+	Decrypt(ciphertext string) (string, error)
+}
+
+// orderEntry is the cached representation of an order. Domain types carry no
+// serialization tags, so the wire format is pinned here instead. Missing marks
+// a negative entry for an ID that is known not to exist. The address's
+// personal fields are stored encrypted, as in MongoDB.
+type orderEntry struct {
+	Missing         bool             `json:"missing,omitempty"`
+	ID              string           `json:"id,omitempty"`
+	UserID          string           `json:"user_id,omitempty"`
+	Items           []orderItemEntry `json:"items,omitempty"`
+	DeliveryFee     float64          `json:"delivery_fee,omitempty"`
+	TotalPrice      float64          `json:"total_price,omitempty"`
+	Currency        string           `json:"currency,omitempty"`
+	Status          string           `json:"status,omitempty"`
+	DeliveryAddress *addressEntry    `json:"delivery_address,omitempty"`
+	DeliveryTime    time.Time        `json:"delivery_time"`
+	CourierID       string           `json:"courier_id,omitempty"`
+	CreatedAt       time.Time        `json:"created_at"`
+	UpdatedAt       time.Time        `json:"updated_at"`
+}
+
+type orderItemEntry struct {
+	ProductID   string  `json:"product_id"`
+	ProductName string  `json:"product_name"`
+	Quantity    int32   `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	TotalPrice  float64 `json:"total_price"`
+}
+
+// addressEntry is a delivery address with the name, street, apartment, phone
+// and access code encrypted.
+type addressEntry struct {
+	ID            string `json:"id"`
+	UserID        string `json:"user_id"`
+	FullName      string `json:"full_name"`
+	StreetAddress string `json:"street_address"`
+	Apartment     string `json:"apartment,omitempty"`
+	City          string `json:"city"`
+	State         string `json:"state"`
+	PostalCode    string `json:"postal_code"`
+	Country       string `json:"country"`
+	Phone         string `json:"phone"`
+	IsDefault     bool   `json:"is_default"`
+
+	Location    *geoPointEntry    `json:"location,omitempty"`
+	Preferences *preferencesEntry `json:"preferences,omitempty"`
+}
+
+type preferencesEntry struct {
+	Instructions  string `json:"instructions,omitempty"`
+	LeaveAtDoor   bool   `json:"leave_at_door,omitempty"`
+	Floor         string `json:"floor,omitempty"`
+	HasElevator   bool   `json:"has_elevator,omitempty"`
+	ContactMethod string `json:"contact_method,omitempty"`
+	AccessCode    string `json:"access_code,omitempty"`
+}
+
+func newPreferencesEntry(p domain.DeliveryPreferences) *preferencesEntry {
+	if p == (domain.DeliveryPreferences{}) {
+		return nil
+	}
+	return &preferencesEntry{
+		Instructions:  p.Instructions,
+		LeaveAtDoor:   p.LeaveAtDoor,
+		Floor:         p.Floor,
+		HasElevator:   p.HasElevator,
+		ContactMethod: string(p.ContactMethod),
+		AccessCode:    p.AccessCode,
+	}
+}
+
+func (e *preferencesEntry) toDomain() domain.DeliveryPreferences {
+	if e == nil {
+		return domain.DeliveryPreferences{}
+	}
+	return domain.DeliveryPreferences{
+		Instructions:  e.Instructions,
+		LeaveAtDoor:   e.LeaveAtDoor,
+		Floor:         e.Floor,
+		HasElevator:   e.HasElevator,
+		ContactMethod: domain.ContactMethod(e.ContactMethod),
+		AccessCode:    e.AccessCode,
+	}
+}
+
+type geoPointEntry struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+}
+
+func newGeoPointEntry(point *domain.GeoPoint) *geoPointEntry {
+	if point == nil {
+		return nil
+	}
+	return &geoPointEntry{Latitude: point.Latitude, Longitude: point.Longitude}
+}
+
+func (e *geoPointEntry) toDomain() *domain.GeoPoint {
+	if e == nil {
+		return nil
+	}
+	return &domain.GeoPoint{Latitude: e.Latitude, Longitude: e.Longitude}
+}
+
+func newAddressEntry(a *domain.DeliveryAddress, cipher FieldCipher) (*addressEntry, error) {
+	if a == nil {
+		return nil, nil
+	}
+
+	entry := &addressEntry{
+		ID:            a.ID,
+		UserID:        a.UserID,
+		FullName:      a.FullName,
+		StreetAddress: a.StreetAddress,
+		Apartment:     a.Apartment,
+		City:          a.City,
+		State:         a.State,
+		PostalCode:    a.PostalCode,
+		Country:       a.Country,
+		Phone:         a.Phone,
+		IsDefault:     a.IsDefault,
+		Location:      newGeoPointEntry(a.Location),
+		Preferences:   newPreferencesEntry(a.Preferences),
+	}
+
+	fields := []*string{&entry.FullName, &entry.StreetAddress, &entry.Apartment, &entry.Phone}
+	if entry.Preferences != nil {
+		fields = append(fields, &entry.Preferences.AccessCode)
+	}
+	for _, field := range fields {
+		ciphertext, err := cipher.Encrypt(*field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt cached address: %v", err)
+		}
+		*field = ciphertext
+	}
+	return entry, nil
+}
+
+func (e *addressEntry) toDomain(cipher FieldCipher) (*domain.DeliveryAddress, error) {
+	if e == nil {
+		return nil, nil
+	}
+
+	address := &domain.DeliveryAddress{
+		ID:            e.ID,
+		UserID:        e.UserID,
+		FullName:      e.FullName,
+		StreetAddress: e.StreetAddress,
+		Apartment:     e.Apartment,
+		City:          e.City,
+		State:         e.State,
+		PostalCode:    e.PostalCode,
+		Country:       e.Country,
+		Phone:         e.Phone,
+		IsDefault:     e.IsDefault,
+		Location:      e.Location.toDomain(),
+		Preferences:   e.Preferences.toDomain(),
+	}
+
+	fields := []*string{&address.FullName, &address.StreetAddress, &address.Apartment, &address.Phone, &address.Preferences.AccessCode}
+	for _, field := range fields {
+		plaintext, err := cipher.Decrypt(*field)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt cached address: %v", err)
+		}
+		*field = plaintext
+	}
+	return address, nil
+}
+
+func newOrderEntry(order *domain.Order, cipher FieldCipher) (*orderEntry, error) {
+	items := make([]orderItemEntry, len(order.Items))
+	for i, item := range order.Items {
+		items[i] = orderItemEntry{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TotalPrice:  item.TotalPrice,
+		}
+	}
+
+	address, err := newAddressEntry(order.DeliveryAddress, cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderEntry{
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Items:           items,
+		DeliveryFee:     order.DeliveryFee,
+		TotalPrice:      order.TotalPrice,
+		Currency:        order.Currency,
+		Status:          string(order.Status),
+		DeliveryAddress: address,
+		DeliveryTime:    order.DeliveryTime,
+		CourierID:       order.CourierID,
+		CreatedAt:       order.CreatedAt,
+		UpdatedAt:       order.UpdatedAt,
+	}, nil
+}
+
+func (e *orderEntry) toDomain(cipher FieldCipher) (*domain.Order, error) {
+	items := make([]domain.OrderItem, len(e.Items))
+	for i, item := range e.Items {
+		items[i] = domain.OrderItem{
+			ProductID:   item.ProductID,
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			TotalPrice:  item.TotalPrice,
+		}
+	}
+
+	address, err := e.DeliveryAddress.toDomain(cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	return &domain.Order{
+		ID:              e.ID,
+		UserID:          e.UserID,
+		Items:           items,
+		DeliveryFee:     e.DeliveryFee,
+		TotalPrice:      e.TotalPrice,
+		Currency:        e.Currency,
+		Status:          domain.OrderStatus(e.Status),
+		DeliveryAddress: address,
+		DeliveryTime:    e.DeliveryTime,
+		CourierID:       e.CourierID,
+		CreatedAt:       e.CreatedAt,
+		UpdatedAt:       e.UpdatedAt,
+	}, nil
+}
+
+// addressListCodec stores address lists as msgpack of addressEntry, so the
+// personal fields are encrypted in Redis while the in-process tier keeps
+// domain values.
+type addressListCodec struct {
+	cipher FieldCipher
+}
+
+func (c addressListCodec) Marshal(addresses []*domain.DeliveryAddress) ([]byte, error) {
+	entries := make([]*addressEntry, len(addresses))
+	for i, address := range addresses {
+		entry, err := newAddressEntry(address, c.cipher)
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = entry
+	}
+	return msgpack.Marshal(entries)
+}
+
+func (c addressListCodec) Unmarshal(data []byte) ([]*domain.DeliveryAddress, error) {
+	var entries []*addressEntry
+	if err := msgpack.Unmarshal(data, &entries); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*domain.DeliveryAddress, len(entries))
+	for i, entry := range entries {
+		address, err := entry.toDomain(c.cipher)
+		if err != nil {
+			return nil, err
+		}
+		addresses[i] = address
+	}
+	return addresses, nil
+}
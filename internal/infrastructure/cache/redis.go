@@ -1,143 +1,201 @@
-package cache
-
-import (
-	"context"
-	"encoding/json"
-	"time"
-
-	"github.com/go-redis/redis/v8"
-	"github.com/hsibAD/order-service/internal/domain"
-)
-
-type RedisCache struct {
-	client *redis.Client
-}
-
-func NewRedisCache(addr string, password string, db int) *RedisCache {
-	client := redis.NewClient(&redis.Options{
-		Addr:     addr,
-		Password: password,
-		DB:       db,
-	})
-
-	return &RedisCache{
-		client: client,
-	}
-}
-
-func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl int) error {
-	data, err := json.Marshal(value)
-	if err != nil {
-		return err
-	}
-
-	return c.client.Set(ctx, key, data, time.Duration(ttl)*time.Second).Err()
-}
-
-func (c *RedisCache) Get(ctx context.Context, key string) (interface{}, error) {
-	data, err := c.client.Get(ctx, key).Bytes()
-	if err != nil {
-		if err == redis.Nil {
-			return nil, nil
-		}
-		return nil, err
-	}
-
-	var value interface{}
-	if err := json.Unmarshal(data, &value); err != nil {
-		return nil, err
-	}
-
-	return value, nil
-}
-
-func (c *RedisCache) Delete(ctx context.Context, key string) error {
-	return c.client.Del(ctx, key).Err()
-}
-
-// Order-specific cache methods
-func (c *RedisCache) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
-	key := "order:" + orderID
-	data, err := c.client.Get(ctx, key).Bytes()
-	if err != nil {
-		if err == redis.Nil {
-			return nil, nil
-		}
-		return nil, err
-	}
-
-	var order domain.Order
-	if err := json.Unmarshal(data, &order); err != nil {
-		return nil, err
-	}
-
-	return &order, nil
-}
-
-func (c *RedisCache) SetOrder(ctx context.Context, order *domain.Order, ttl int) error {
-	key := "order:" + order.ID
-	return c.Set(ctx, key, order, ttl)
-}
-
-func (c *RedisCache) DeleteOrder(ctx context.Context, orderID string) error {
-	key := "order:" + orderID
-	return c.Delete(ctx, key)
-}
-
-// Delivery address cache methods
-func (c *RedisCache) GetDeliveryAddresses(ctx context.Context, userID string) ([]*domain.DeliveryAddress, error) {
-	key := "addresses:" + userID
-	data, err := c.client.Get(ctx, key).Bytes()
-	if err != nil {
-		if err == redis.Nil {
-			return nil, nil
-		}
-		return nil, err
-	}
-
-	var addresses []*domain.DeliveryAddress
-	if err := json.Unmarshal(data, &addresses); err != nil {
-		return nil, err
-	}
-
-	return addresses, nil
-}
-
-func (c *RedisCache) SetDeliveryAddresses(ctx context.Context, userID string, addresses []*domain.DeliveryAddress, ttl int) error {
-	key := "addresses:" + userID
-	return c.Set(ctx, key, addresses, ttl)
-}
-
-func (c *RedisCache) DeleteDeliveryAddresses(ctx context.Context, userID string) error {
-	key := "addresses:" + userID
-	return c.Delete(ctx, key)
-}
-
-// Delivery slots cache methods
-func (c *RedisCache) GetDeliverySlots(ctx context.Context, date string) ([]*domain.DeliverySlot, error) {
-	key := "slots:" + date
-	data, err := c.client.Get(ctx, key).Bytes()
-	if err != nil {
-		if err == redis.Nil {
-			return nil, nil
-		}
-		return nil, err
-	}
-
-	var slots []*domain.DeliverySlot
-	if err := json.Unmarshal(data, &slots); err != nil {
-		return nil, err
-	}
-
-	return slots, nil
-}
-
-func (c *RedisCache) SetDeliverySlots(ctx context.Context, date string, slots []*domain.DeliverySlot, ttl int) error {
-	key := "slots:" + date
-	return c.Set(ctx, key, slots, ttl)
-}
-
-func (c *RedisCache) DeleteDeliverySlots(ctx context.Context, date string) error {
-	key := "slots:" + date
-	return c.Delete(ctx, key)
-} 
\ No newline at end of file
+package cache
+
+import (
+	"context"
+	"crypto/tls"
+	"sync"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// Stored format versions, bump on any change to the cached representation
+const (
+	orderCacheVersion     = 5
+	addressesCacheVersion = 4
+	slotsCacheVersion     = 2
+	geocodeCacheVersion   = 1
+)
+
+type RedisConfig struct {
+	Addr      string
+	Password  string
+	DB        int
+	Namespace string
+
+	// In-process tier in front of Redis for address lists and slots
+	L1Size int
+	L1TTL  time.Duration
+
+	TLSConfig *tls.Config // Plaintext when nil
+
+	// Encrypts personal address fields in cached orders and address lists
+	Cipher FieldCipher
+}
+
+type RedisCache struct {
+	client    *redis.Client
+	cipher    FieldCipher
+	orders    *RedisTypedCache[*orderEntry]
+	addresses *TieredCache[[]*domain.DeliveryAddress]
+	slots     *TieredCache[[]*domain.DeliverySlot]
+	geocodes  *RedisTypedCache[*geoPointEntry]
+}
+
+func NewRedisCache(config RedisConfig) *RedisCache {
+	client := redis.NewClient(&redis.Options{
+		Addr:      config.Addr,
+		Password:  config.Password,
+		DB:        config.DB,
+		TLSConfig: config.TLSConfig,
+	})
+	bus := NewInvalidationBus(client)
+
+	addressKeys := Keyspace{Namespace: config.Namespace, Name: "addresses", Version: addressesCacheVersion}
+	slotKeys := Keyspace{Namespace: config.Namespace, Name: "slots", Version: slotsCacheVersion}
+
+	return &RedisCache{
+		client: client,
+		cipher: config.Cipher,
+		orders: NewRedisTypedCache[*orderEntry](client,
+			Keyspace{Namespace: config.Namespace, Name: "order", Version: orderCacheVersion},
+			JSONCodec[*orderEntry]{}),
+		addresses: NewTieredCache[[]*domain.DeliveryAddress](
+			NewMemoryCache[[]*domain.DeliveryAddress](config.L1Size),
+			config.L1TTL,
+			NewRedisTypedCache[[]*domain.DeliveryAddress](client, addressKeys, addressListCodec{cipher: config.Cipher}),
+			bus,
+			addressKeys.Channel(),
+		),
+		slots: NewTieredCache[[]*domain.DeliverySlot](
+			NewMemoryCache[[]*domain.DeliverySlot](config.L1Size),
+			config.L1TTL,
+			NewRedisTypedCache[[]*domain.DeliverySlot](client, slotKeys, MsgpackCodec[[]*domain.DeliverySlot]{}),
+			bus,
+			slotKeys.Channel(),
+		),
+		geocodes: NewRedisTypedCache[*geoPointEntry](client,
+			Keyspace{Namespace: config.Namespace, Name: "geocode", Version: geocodeCacheVersion},
+			JSONCodec[*geoPointEntry]{}),
+	}
+}
+
+// ListenForInvalidations keeps the in-process tiers in sync with the other
+// replicas until ctx is done, resubscribing whenever Redis drops out.
+func (c *RedisCache) ListenForInvalidations(ctx context.Context) {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() { defer wg.Done(); c.addresses.Listen(ctx) }()
+	go func() { defer wg.Done(); c.slots.Listen(ctx) }()
+	wg.Wait()
+}
+
+// Stats reports hit/miss counters of the tiered caches by name.
+func (c *RedisCache) Stats() map[string]TierStats {
+	return map[string]TierStats{
+		"addresses": c.addresses.Stats(),
+		"slots":     c.slots.Stats(),
+	}
+}
+
+// Check pings Redis, for readiness probes.
+func (c *RedisCache) Check(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
+
+func (c *RedisCache) Close() error {
+	return c.client.Close()
+}
+
+// Client exposes the underlying connection for other Redis-backed components.
+func (c *RedisCache) Client() *redis.Client {
+	return c.client
+}
+
+// Order-specific cache methods
+
+// GetOrder returns nil, nil on a cache miss and domain.ErrInvalidOrderID when
+// the order was cached as not found.
+func (c *RedisCache) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
+	entry, ok, err := c.orders.Get(ctx, orderID)
+	if err != nil || !ok {
+		return nil, err
+	}
+
+	if entry.Missing {
+		return nil, domain.ErrInvalidOrderID
+	}
+
+	return entry.toDomain(c.cipher)
+}
+
+// OrderGeneration returns the invalidation counter of orderID. Read it before
+// loading the order and pass it to SetOrder or SetOrderNotFound.
+func (c *RedisCache) OrderGeneration(ctx context.Context, orderID string) (int64, error) {
+	return c.orders.Generation(ctx, orderID)
+}
+
+// SetOrder caches order unless it was invalidated after generation was read,
+// in which case the possibly stale copy is dropped.
+func (c *RedisCache) SetOrder(ctx context.Context, order *domain.Order, generation int64, ttl time.Duration) error {
+	entry, err := newOrderEntry(order, c.cipher)
+	if err != nil {
+		return err
+	}
+	_, err = c.orders.SetIfGeneration(ctx, order.ID, entry, generation, ttl)
+	return err
+}
+
+// SetOrderNotFound records that orderID does not exist so repeated lookups
+// of unknown IDs don't reach the database. Like SetOrder it is dropped when
+// the order was written after generation was read.
+func (c *RedisCache) SetOrderNotFound(ctx context.Context, orderID string, generation int64, ttl time.Duration) error {
+	_, err := c.orders.SetIfGeneration(ctx, orderID, &orderEntry{Missing: true}, generation, ttl)
+	return err
+}
+
+func (c *RedisCache) DeleteOrder(ctx context.Context, orderID string) error {
+	return c.orders.Invalidate(ctx, orderID)
+}
+
+// Delivery address cache methods
+func (c *RedisCache) GetDeliveryAddresses(ctx context.Context, userID string) ([]*domain.DeliveryAddress, error) {
+	addresses, _, err := c.addresses.Get(ctx, userID)
+	return addresses, err
+}
+
+func (c *RedisCache) SetDeliveryAddresses(ctx context.Context, userID string, addresses []*domain.DeliveryAddress, ttl time.Duration) error {
+	return c.addresses.Set(ctx, userID, addresses, ttl)
+}
+
+func (c *RedisCache) DeleteDeliveryAddresses(ctx context.Context, userID string) error {
+	return c.addresses.Delete(ctx, userID)
+}
+
+// Delivery slots cache methods
+func (c *RedisCache) GetDeliverySlots(ctx context.Context, date string) ([]*domain.DeliverySlot, error) {
+	slots, _, err := c.slots.Get(ctx, date)
+	return slots, err
+}
+
+func (c *RedisCache) SetDeliverySlots(ctx context.Context, date string, slots []*domain.DeliverySlot, ttl time.Duration) error {
+	return c.slots.Set(ctx, date, slots, ttl)
+}
+
+func (c *RedisCache) DeleteDeliverySlots(ctx context.Context, date string) error {
+	return c.slots.Delete(ctx, date)
+}
+
+// Geocoding cache methods
+func (c *RedisCache) GetGeocode(ctx context.Context, key string) (*domain.GeoPoint, bool, error) {
+	entry, ok, err := c.geocodes.Get(ctx, key)
+	if err != nil || !ok {
+		return nil, false, err
+	}
+	return entry.toDomain(), true, nil
+}
+
+func (c *RedisCache) SetGeocode(ctx context.Context, key string, point *domain.GeoPoint, ttl time.Duration) error {
+	return c.geocodes.Set(ctx, key, newGeoPointEntry(point), ttl)
+}
\ No newline at end of file
@@ -0,0 +1,146 @@
+package cache
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// TierStats counts lookups per tier since start-up.
+type TierStats struct {
+	L1Hits   uint64
+	L1Misses uint64
+	L2Hits   uint64
+	L2Misses uint64
+}
+
+// TieredCache keeps a short-lived in-process L1 in front of a shared L2.
+// Deletes are broadcast on the invalidation bus so other replicas drop their
+// L1 copies too; writers must delete rather than overwrite changed entries.
+type TieredCache[T any] struct {
+	l1      *MemoryCache[T]
+	l1TTL   time.Duration
+	l2      domain.Cache[T]
+	bus     *InvalidationBus
+	channel string
+
+	l1Hits   atomic.Uint64
+	l1Misses atomic.Uint64
+	l2Hits   atomic.Uint64
+	l2Misses atomic.Uint64
+}
+
+func NewTieredCache[T any](l1 *MemoryCache[T], l1TTL time.Duration, l2 domain.Cache[T], bus *InvalidationBus, channel string) *TieredCache[T] {
+	return &TieredCache[T]{
+		l1:      l1,
+		l1TTL:   l1TTL,
+		l2:      l2,
+		bus:     bus,
+		channel: channel,
+	}
+}
+
+func (c *TieredCache[T]) Get(ctx context.Context, key string) (T, bool, error) {
+	if value, ok, _ := c.l1.Get(ctx, key); ok {
+		c.l1Hits.Add(1)
+		return value, true, nil
+	}
+	c.l1Misses.Add(1)
+
+	value, ok, err := c.l2.Get(ctx, key)
+	if err != nil || !ok {
+		c.l2Misses.Add(1)
+		return value, ok, err
+	}
+	c.l2Hits.Add(1)
+
+	c.l1.Set(ctx, key, value, c.l1TTL)
+	return value, true, nil
+}
+
+func (c *TieredCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) error {
+	if err := c.l2.Set(ctx, key, value, ttl); err != nil {
+		return err
+	}
+
+	return c.l1.Set(ctx, key, value, c.localTTL(ttl))
+}
+
+func (c *TieredCache[T]) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	c.l1.Delete(ctx, keys...)
+	if err := c.l2.Delete(ctx, keys...); err != nil {
+		return err
+	}
+
+	return c.bus.Publish(ctx, c.channel, keys)
+}
+
+func (c *TieredCache[T]) MGet(ctx context.Context, keys ...string) (map[string]T, error) {
+	values, _ := c.l1.MGet(ctx, keys...)
+	c.l1Hits.Add(uint64(len(values)))
+
+	var missing []string
+	for _, key := range keys {
+		if _, ok := values[key]; !ok {
+			missing = append(missing, key)
+		}
+	}
+	c.l1Misses.Add(uint64(len(missing)))
+	if len(missing) == 0 {
+		return values, nil
+	}
+
+	found, err := c.l2.MGet(ctx, missing...)
+	if err != nil {
+		return nil, err
+	}
+	c.l2Hits.Add(uint64(len(found)))
+	c.l2Misses.Add(uint64(len(missing) - len(found)))
+
+	c.l1.MSet(ctx, found, c.l1TTL)
+	for key, value := range found {
+		values[key] = value
+	}
+
+	return values, nil
+}
+
+func (c *TieredCache[T]) MSet(ctx context.Context, values map[string]T, ttl time.Duration) error {
+	if err := c.l2.MSet(ctx, values, ttl); err != nil {
+		return err
+	}
+
+	return c.l1.MSet(ctx, values, c.localTTL(ttl))
+}
+
+// Listen drops L1 entries invalidated by any replica until ctx is done.
+// After the subscription was interrupted the whole L1 is dropped, as
+// invalidations may have been missed.
+func (c *TieredCache[T]) Listen(ctx context.Context) {
+	c.bus.Subscribe(ctx, c.channel, func(keys []string) {
+		c.l1.Delete(ctx, keys...)
+	}, c.l1.Clear)
+}
+
+func (c *TieredCache[T]) Stats() TierStats {
+	return TierStats{
+		L1Hits:   c.l1Hits.Load(),
+		L1Misses: c.l1Misses.Load(),
+		L2Hits:   c.l2Hits.Load(),
+		L2Misses: c.l2Misses.Load(),
+	}
+}
+
+// localTTL caps L1 lifetimes so a missed invalidation heals quickly.
+func (c *TieredCache[T]) localTTL(ttl time.Duration) time.Duration {
+	if ttl <= 0 || ttl > c.l1TTL {
+		return c.l1TTL
+	}
+	return ttl
+}
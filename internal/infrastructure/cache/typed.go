@@ -0,0 +1,237 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/hsibAD/order-service/internal/metrics"
+	"github.com/hsibAD/order-service/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Keyspace namespaces the keys of one kind of value. Bump Version whenever
+// the stored format changes so old and new replicas never read each other's
+// entries during a rolling deploy.
+type Keyspace struct {
+	Namespace string
+	Name      string
+	Version   int
+}
+
+func (k Keyspace) Key(key string) string {
+	return fmt.Sprintf("%s:v%d:%s:%s", k.Namespace, k.Version, k.Name, key)
+}
+
+// Channel is the pub/sub channel carrying invalidations for the keyspace.
+func (k Keyspace) Channel() string {
+	return fmt.Sprintf("%s:v%d:%s:invalidate", k.Namespace, k.Version, k.Name)
+}
+
+// RedisTypedCache is a domain.Cache backed by Redis.
+type RedisTypedCache[T any] struct {
+	client *redis.Client
+	keys   Keyspace
+	codec  Codec[T]
+}
+
+func NewRedisTypedCache[T any](client *redis.Client, keys Keyspace, codec Codec[T]) *RedisTypedCache[T] {
+	return &RedisTypedCache[T]{
+		client: client,
+		keys:   keys,
+		codec:  codec,
+	}
+}
+
+func (c *RedisTypedCache[T]) Get(ctx context.Context, key string) (_ T, _ bool, err error) {
+	ctx, span := c.startSpan(ctx, "Get")
+	defer func() { tracing.End(span, err) }()
+
+	var zero T
+	data, err := c.client.Get(ctx, c.keys.Key(key)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			c.count("miss", 1)
+			return zero, false, nil
+		}
+		c.count("error", 1)
+		return zero, false, err
+	}
+
+	value, err := c.codec.Unmarshal(data)
+	if err != nil {
+		c.count("error", 1)
+		return zero, false, err
+	}
+
+	c.count("hit", 1)
+	return value, true, nil
+}
+
+func (c *RedisTypedCache[T]) Set(ctx context.Context, key string, value T, ttl time.Duration) (err error) {
+	ctx, span := c.startSpan(ctx, "Set")
+	defer func() { tracing.End(span, err) }()
+
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, c.keys.Key(key), data, ttl).Err()
+}
+
+func (c *RedisTypedCache[T]) Delete(ctx context.Context, keys ...string) (err error) {
+	if len(keys) == 0 {
+		return nil
+	}
+	ctx, span := c.startSpan(ctx, "Delete", attribute.Int("cache.keys", len(keys)))
+	defer func() { tracing.End(span, err) }()
+
+	return c.client.Del(ctx, c.fullKeys(keys)...).Err()
+}
+
+// MGet returns the entries that were found; missing keys are left out.
+func (c *RedisTypedCache[T]) MGet(ctx context.Context, keys ...string) (_ map[string]T, err error) {
+	values := make(map[string]T, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+	ctx, span := c.startSpan(ctx, "MGet", attribute.Int("cache.keys", len(keys)))
+	defer func() { tracing.End(span, err) }()
+
+	results, err := c.client.MGet(ctx, c.fullKeys(keys)...).Result()
+	if err != nil {
+		c.count("error", len(keys))
+		return nil, err
+	}
+
+	for i, result := range results {
+		data, ok := result.(string)
+		if !ok {
+			continue
+		}
+
+		value, err := c.codec.Unmarshal([]byte(data))
+		if err != nil {
+			c.count("error", len(keys))
+			return nil, err
+		}
+		values[keys[i]] = value
+	}
+
+	c.count("hit", len(values))
+	c.count("miss", len(keys)-len(values))
+	return values, nil
+}
+
+// MSet writes all values in one round trip. MSET has no expiry, so the
+// entries are pipelined as individual SETs instead.
+func (c *RedisTypedCache[T]) MSet(ctx context.Context, values map[string]T, ttl time.Duration) (err error) {
+	if len(values) == 0 {
+		return nil
+	}
+	ctx, span := c.startSpan(ctx, "MSet", attribute.Int("cache.keys", len(values)))
+	defer func() { tracing.End(span, err) }()
+
+	pipe := c.client.Pipeline()
+	for key, value := range values {
+		data, err := c.codec.Marshal(value)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, c.keys.Key(key), data, ttl)
+	}
+
+	_, err = pipe.Exec(ctx)
+	return err
+}
+
+// Generations let a fill computed from a database read be dropped if the
+// entry was invalidated since that read. The counter must outlive any fill in
+// progress, so it only expires after being left alone for generationTTL.
+const generationTTL = time.Hour
+
+// setIfGeneration stores ARGV[2] at KEYS[1] only while the generation counter
+// at KEYS[2] still holds ARGV[1]; a missing counter counts as 0.
+var setIfGeneration = redis.NewScript(`
+if (redis.call('GET', KEYS[2]) or '0') ~= ARGV[1] then
+	return 0
+end
+if ARGV[3] == '0' then
+	redis.call('SET', KEYS[1], ARGV[2])
+else
+	redis.call('SET', KEYS[1], ARGV[2], 'PX', ARGV[3])
+end
+return 1
+`)
+
+// Generation returns the invalidation counter of key, to be read before
+// loading the value that SetIfGeneration will store.
+func (c *RedisTypedCache[T]) Generation(ctx context.Context, key string) (int64, error) {
+	generation, err := c.client.Get(ctx, c.generationKey(key)).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return generation, err
+}
+
+// SetIfGeneration stores value unless key was invalidated after generation
+// was read. It reports whether the value was stored.
+func (c *RedisTypedCache[T]) SetIfGeneration(ctx context.Context, key string, value T, generation int64, ttl time.Duration) (_ bool, err error) {
+	ctx, span := c.startSpan(ctx, "SetIfGeneration")
+	defer func() { tracing.End(span, err) }()
+
+	data, err := c.codec.Marshal(value)
+	if err != nil {
+		return false, err
+	}
+
+	stored, err := setIfGeneration.Run(ctx, c.client,
+		[]string{c.keys.Key(key), c.generationKey(key)},
+		generation, data, ttl.Milliseconds(),
+	).Int()
+	return stored == 1, err
+}
+
+// Invalidate deletes key and bumps its generation, so fills that read the
+// old value are dropped.
+func (c *RedisTypedCache[T]) Invalidate(ctx context.Context, key string) (err error) {
+	ctx, span := c.startSpan(ctx, "Invalidate")
+	defer func() { tracing.End(span, err) }()
+
+	_, err = c.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Del(ctx, c.keys.Key(key))
+		pipe.Incr(ctx, c.generationKey(key))
+		pipe.PExpire(ctx, c.generationKey(key), generationTTL)
+		return nil
+	})
+	return err
+}
+
+func (c *RedisTypedCache[T]) generationKey(key string) string {
+	return c.keys.Key(key) + ":gen"
+}
+
+func (c *RedisTypedCache[T]) fullKeys(keys []string) []string {
+	fullKeys := make([]string, len(keys))
+	for i, key := range keys {
+		fullKeys[i] = c.keys.Key(key)
+	}
+	return fullKeys
+}
+
+// count records lookups in metrics.CacheRequests under the keyspace name.
+func (c *RedisTypedCache[T]) count(result string, n int) {
+	if n > 0 {
+		metrics.CacheRequests.WithLabelValues(c.keys.Name, result).Add(float64(n))
+	}
+}
+
+func (c *RedisTypedCache[T]) startSpan(ctx context.Context, op string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "cache."+op, trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(append([]attribute.KeyValue{
+		attribute.String("db.system", "redis"),
+		attribute.String("cache.keyspace", c.keys.Name),
+	}, attrs...)...))
+}
@@ -0,0 +1,66 @@
+package cache
+
+import (
+	"context"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func TestRedisTypedCacheMGetMSet(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	keys := Keyspace{Namespace: "test", Name: "values", Version: 1}
+	c := NewRedisTypedCache[*codecValue](client, keys, MsgpackCodec[*codecValue]{})
+	ctx := context.Background()
+
+	values := map[string]*codecValue{
+		"a": {ID: "a", Count: 1},
+		"b": {ID: "b", Count: 2},
+	}
+	if err := c.MSet(ctx, values, time.Minute); err != nil {
+		t.Fatalf("MSet: %v", err)
+	}
+	if ttl := server.TTL(keys.Key("a")); ttl != time.Minute {
+		t.Errorf("TTL = %v, want 1m", ttl)
+	}
+
+	got, err := c.MGet(ctx, "a", "missing", "b")
+	if err != nil {
+		t.Fatalf("MGet: %v", err)
+	}
+	for _, value := range got {
+		value.At = time.Time{}
+	}
+	if !reflect.DeepEqual(got, values) {
+		t.Errorf("MGet = %v, want %v", got, values)
+	}
+
+	server.FastForward(time.Minute)
+	got, err = c.MGet(ctx, "a", "b")
+	if err != nil {
+		t.Fatalf("MGet: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("MGet after expiry = %v, want none", got)
+	}
+}
+
+func TestRedisTypedCacheRejectsCorruptEntries(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+	keys := Keyspace{Namespace: "test", Name: "values", Version: 1}
+	c := NewRedisTypedCache[*codecValue](client, keys, JSONCodec[*codecValue]{})
+
+	if err := server.Set(keys.Key("a"), "not json"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.MGet(context.Background(), "a"); err == nil {
+		t.Error("MGet decoded a corrupt entry")
+	}
+}
@@ -0,0 +1,339 @@
+package encryption
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrUnknownKey = errors.New("unknown encryption key")
+
+// Ciphertext prefixes. Values without one were written by the legacy
+// single-key cipher.
+const (
+	envelopePrefix      = "enc:1:"
+	deterministicPrefix = "det:1:"
+)
+
+// Data keys are reused for a while so that writing a document doesn't wrap a
+// fresh key per field.
+const (
+	dataKeyLifetime = time.Hour
+	dataKeyMaxUses  = 1 << 20
+)
+
+// Keyring does envelope encryption: values are sealed with a random data key,
+// which is itself wrapped by the active master key and stored alongside:
+//
+//	enc:1:<master key id>:<wrapped data key>:<sealed value>
+//
+// Deterministic values, for fields that are queried by equality, are sealed
+// with a key and nonce derived from the master key and the value:
+//
+//	det:1:<master key id>:<sealed value>
+//
+// Older master keys stay in the ring for decryption until every value has
+// been rewritten under the active one.
+type Keyring struct {
+	masters       map[string]*masterKey
+	active        string
+	legacy        *Cipher // Single-key format used before envelopes, optional
+	mu            sync.Mutex
+	dataKey       *dataKey
+	unwrappedKeys sync.Map // Wrapped data key -> *Cipher
+}
+
+type masterKey struct {
+	wrap          *Cipher
+	deterministic *Cipher
+	nonceKey      []byte
+}
+
+type dataKey struct {
+	cipher  *Cipher
+	wrapped string
+	created time.Time
+	uses    int
+}
+
+// NewKeyring builds a ring from 32 byte master keys by ID.
+func NewKeyring(keys map[string][]byte, active string, legacy *Cipher) (*Keyring, error) {
+	if _, ok := keys[active]; !ok {
+		return nil, fmt.Errorf("active encryption key %q is not configured", active)
+	}
+
+	masters := make(map[string]*masterKey, len(keys))
+	for id, key := range keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("invalid encryption key id %q", id)
+		}
+
+		wrap, err := NewCipher(key)
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s: %v", id, err)
+		}
+		deterministic, err := NewCipher(deriveKey(key, "deterministic encryption"))
+		if err != nil {
+			return nil, fmt.Errorf("encryption key %s: %v", id, err)
+		}
+
+		masters[id] = &masterKey{
+			wrap:          wrap,
+			deterministic: deterministic,
+			nonceKey:      deriveKey(key, "deterministic nonce"),
+		}
+	}
+
+	return &Keyring{
+		masters: masters,
+		active:  active,
+		legacy:  legacy,
+	}, nil
+}
+
+// ActiveKeyID names the master key new values are written under.
+func (k *Keyring) ActiveKeyID() string {
+	return k.active
+}
+
+// Encrypt seals plaintext under a data key. Equal inputs give different
+// outputs.
+func (k *Keyring) Encrypt(plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	key, err := k.currentDataKey()
+	if err != nil {
+		return "", err
+	}
+
+	sealed, err := key.cipher.seal([]byte(plaintext))
+	if err != nil {
+		return "", err
+	}
+	return envelopePrefix + k.active + ":" + key.wrapped + ":" + encode(sealed), nil
+}
+
+// EncryptDeterministic seals plaintext so equal inputs give equal outputs
+// under the same master key, which allows equality queries on the field.
+func (k *Keyring) EncryptDeterministic(plaintext string) (string, error) {
+	return k.encryptDeterministic(k.active, plaintext)
+}
+
+// DeterministicCandidates returns plaintext encrypted under every master key
+// in the ring, for $in queries that must also match values not yet rotated.
+func (k *Keyring) DeterministicCandidates(plaintext string) ([]string, error) {
+	candidates := make([]string, 0, len(k.masters))
+	for id := range k.masters {
+		ciphertext, err := k.encryptDeterministic(id, plaintext)
+		if err != nil {
+			return nil, err
+		}
+		candidates = append(candidates, ciphertext)
+	}
+	return candidates, nil
+}
+
+func (k *Keyring) encryptDeterministic(keyID string, plaintext string) (string, error) {
+	if plaintext == "" {
+		return "", nil
+	}
+
+	master, ok := k.masters[keyID]
+	if !ok {
+		return "", ErrUnknownKey
+	}
+
+	mac := hmac.New(sha256.New, master.nonceKey)
+	mac.Write([]byte(plaintext))
+	sealed := master.deterministic.sealWithNonce(mac.Sum(nil), []byte(plaintext))
+	return deterministicPrefix + keyID + ":" + encode(sealed), nil
+}
+
+// Decrypt opens a value written by Encrypt, EncryptDeterministic or the
+// legacy cipher. Anything else fails with ErrInvalidCiphertext; plaintext
+// stored before encryption was enabled must not be passed in.
+func (k *Keyring) Decrypt(value string) (string, error) {
+	switch {
+	case value == "":
+		return "", nil
+
+	case strings.HasPrefix(value, envelopePrefix):
+		parts := strings.Split(strings.TrimPrefix(value, envelopePrefix), ":")
+		if len(parts) != 3 {
+			return "", ErrInvalidCiphertext
+		}
+		key, err := k.unwrap(parts[0], parts[1])
+		if err != nil {
+			return "", err
+		}
+		return openEncoded(key, parts[2])
+
+	case strings.HasPrefix(value, deterministicPrefix):
+		keyID, sealed, ok := strings.Cut(strings.TrimPrefix(value, deterministicPrefix), ":")
+		if !ok {
+			return "", ErrInvalidCiphertext
+		}
+		master, ok := k.masters[keyID]
+		if !ok {
+			return "", ErrUnknownKey
+		}
+		return openEncoded(master.deterministic, sealed)
+
+	case k.legacy != nil:
+		return k.legacy.Decrypt(value)
+	}
+	return "", ErrInvalidCiphertext
+}
+
+func (k *Keyring) currentDataKey() (*dataKey, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.dataKey != nil && k.dataKey.uses < dataKeyMaxUses && time.Since(k.dataKey.created) < dataKeyLifetime {
+		k.dataKey.uses++
+		return k.dataKey, nil
+	}
+
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate data key: %v", err)
+	}
+	dek, err := NewCipher(raw)
+	if err != nil {
+		return nil, err
+	}
+	wrapped, err := k.masters[k.active].wrap.seal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	k.dataKey = &dataKey{
+		cipher:  dek,
+		wrapped: encode(wrapped),
+		created: time.Now(),
+		uses:    1,
+	}
+	k.unwrappedKeys.Store(k.active+":"+k.dataKey.wrapped, dek)
+	return k.dataKey, nil
+}
+
+func (k *Keyring) unwrap(keyID string, wrapped string) (*Cipher, error) {
+	cacheKey := keyID + ":" + wrapped
+	if dek, ok := k.unwrappedKeys.Load(cacheKey); ok {
+		return dek.(*Cipher), nil
+	}
+
+	master, ok := k.masters[keyID]
+	if !ok {
+		return nil, ErrUnknownKey
+	}
+
+	data, err := decode(wrapped)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := master.wrap.open(data)
+	if err != nil {
+		return nil, err
+	}
+	dek, err := NewCipher(raw)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+
+	k.unwrappedKeys.Store(cacheKey, dek)
+	return dek, nil
+}
+
+func openEncoded(c *Cipher, sealed string) (string, error) {
+	data, err := decode(sealed)
+	if err != nil {
+		return "", err
+	}
+	plaintext, err := c.open(data)
+	if err != nil {
+		return "", err
+	}
+	return string(plaintext), nil
+}
+
+// deriveKey gives independent subkeys of a master key for each purpose.
+func deriveKey(master []byte, purpose string) []byte {
+	mac := hmac.New(sha256.New, master)
+	mac.Write([]byte(purpose))
+	return mac.Sum(nil)
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decode(s string) ([]byte, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCiphertext
+	}
+	return data, nil
+}
+
+// keyFile is the on-disk format of LoadKeyFile:
+//
+//	{"active": "2024-06", "keys": {"2024-01": "<base64>", "2024-06": "<base64>"}}
+type keyFile struct {
+	Active string            `json:"active"`
+	Keys   map[string]string `json:"keys"`
+}
+
+// LoadKeyFile reads master keys and the active key ID from a JSON file.
+func LoadKeyFile(path string) (map[string][]byte, string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read encryption keys: %v", err)
+	}
+
+	var file keyFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, "", fmt.Errorf("failed to parse encryption keys: %v", err)
+	}
+
+	keys, err := decodeKeys(file.Keys)
+	if err != nil {
+		return nil, "", err
+	}
+	return keys, file.Active, nil
+}
+
+// ParseKeys reads "id=base64,id=base64" master key lists.
+func ParseKeys(spec string) (map[string][]byte, error) {
+	encoded := make(map[string]string)
+	for _, pair := range strings.Split(spec, ",") {
+		id, key, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid encryption key entry %q", pair)
+		}
+		encoded[id] = key
+	}
+	return decodeKeys(encoded)
+}
+
+func decodeKeys(encoded map[string]string) (map[string][]byte, error) {
+	keys := make(map[string][]byte, len(encoded))
+	for id, value := range encoded {
+		key, err := base64.StdEncoding.DecodeString(value)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("encryption key %s must be 32 bytes of base64", id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
@@ -0,0 +1,160 @@
+package encryption
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func testKeyring(t *testing.T, legacy *Cipher) *Keyring {
+	t.Helper()
+	keys := map[string][]byte{"old": KeyFromSecret("old"), "new": KeyFromSecret("new")}
+	ring, err := NewKeyring(keys, "new", legacy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ring
+}
+
+func TestKeyringRoundTrip(t *testing.T) {
+	legacy, err := NewCipherFromSecret("legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ring := testKeyring(t, legacy)
+
+	sealed, err := ring.Encrypt("+44 20 7946 0958")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, envelopePrefix+"new:") || strings.Contains(sealed, "7946") {
+		t.Fatalf("unexpected ciphertext %q", sealed)
+	}
+
+	legacySealed, err := legacy.Encrypt("4711#")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for ciphertext, want := range map[string]string{sealed: "+44 20 7946 0958", legacySealed: "4711#", "": ""} {
+		got, err := ring.Decrypt(ciphertext)
+		if err != nil || got != want {
+			t.Errorf("Decrypt(%q) = %q, %v; want %q", ciphertext, got, err, want)
+		}
+	}
+}
+
+func TestKeyringDecryptRejectsUnknownValues(t *testing.T) {
+	legacy, err := NewCipherFromSecret("legacy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sealed, err := testKeyring(t, legacy).Encrypt("Jane Roe")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherLegacy, err := NewCipherFromSecret("other")
+	if err != nil {
+		t.Fatal(err)
+	}
+	foreign, err := otherLegacy.Encrypt("Jane Roe")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		ring  *Keyring
+		value string
+	}{
+		"plaintext":            {testKeyring(t, legacy), "221B Baker Street"},
+		"plaintext, no legacy": {testKeyring(t, nil), "221B Baker Street"},
+		"other legacy key":     {testKeyring(t, legacy), foreign},
+		"truncated envelope":   {testKeyring(t, legacy), sealed[:len(sealed)-4]},
+		"malformed envelope":   {testKeyring(t, legacy), envelopePrefix + "new:abc"},
+		"unknown master key":   {testKeyring(t, legacy), strings.Replace(sealed, ":new:", ":gone:", 1)},
+		"malformed det value":  {testKeyring(t, legacy), deterministicPrefix + "new"},
+		"unknown det key":      {testKeyring(t, legacy), deterministicPrefix + "gone:abc"},
+	}
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.ring.Decrypt(tt.value)
+			if err == nil {
+				t.Fatalf("Decrypt(%q) = %q, want an error", tt.value, got)
+			}
+			if !errors.Is(err, ErrInvalidCiphertext) && !errors.Is(err, ErrUnknownKey) {
+				t.Errorf("unexpected error %v", err)
+			}
+		})
+	}
+}
+
+func TestKeyringDeterministic(t *testing.T) {
+	ring := testKeyring(t, nil)
+
+	first, err := ring.EncryptDeterministic("+442079460958")
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := ring.EncryptDeterministic("+442079460958")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second {
+		t.Errorf("equal inputs gave %q and %q", first, second)
+	}
+	if !strings.HasPrefix(first, deterministicPrefix+"new:") || strings.Contains(first, "7946") {
+		t.Fatalf("unexpected ciphertext %q", first)
+	}
+
+	other, err := ring.EncryptDeterministic("+442079460000")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if other == first {
+		t.Error("different inputs gave equal ciphertexts")
+	}
+
+	if got, err := ring.Decrypt(first); err != nil || got != "+442079460958" {
+		t.Errorf("Decrypt = %q, %v", got, err)
+	}
+	if got, err := ring.EncryptDeterministic(""); err != nil || got != "" {
+		t.Errorf("EncryptDeterministic(\"\") = %q, %v; want empty", got, err)
+	}
+}
+
+func TestKeyringDeterministicCandidates(t *testing.T) {
+	keys := map[string][]byte{"old": KeyFromSecret("old")}
+	before, err := NewKeyring(keys, "old", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stored, err := before.EncryptDeterministic("+442079460958")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// After the active key changed, lookups must still find values written
+	// under the old one
+	ring := testKeyring(t, nil)
+	current, err := ring.EncryptDeterministic("+442079460958")
+	if err != nil {
+		t.Fatal(err)
+	}
+	candidates, err := ring.DeterministicCandidates("+442079460958")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candidates) != 2 {
+		t.Fatalf("got %d candidates, want one per master key", len(candidates))
+	}
+	for _, want := range []string{stored, current} {
+		found := false
+		for _, candidate := range candidates {
+			found = found || candidate == want
+		}
+		if !found {
+			t.Errorf("candidates %q lack %q", candidates, want)
+		}
+	}
+}
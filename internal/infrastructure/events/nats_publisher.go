@@ -1,132 +1,355 @@
-package events
-
-import (
-	"context"
-	"encoding/json"
-
-	"github.com/nats-io/nats.go"
-	"github.com/yourusername/order-service/internal/domain"
-)
-
-const (
-	OrderCreatedSubject       = "order.created"
-	OrderStatusUpdatedSubject = "order.status.updated"
-	OrderCancelledSubject     = "order.cancelled"
-)
-
-type NATSPublisher struct {
-	nc *nats.Conn
-	js nats.JetStreamContext
-}
-
-type OrderEvent struct {
-	ID              string                 `json:"id"`
-	UserID          string                 `json:"user_id"`
-	Status          string                 `json:"status"`
-	TotalPrice      float64               `json:"total_price"`
-	Currency        string                 `json:"currency"`
-	DeliveryAddress *domain.DeliveryAddress `json:"delivery_address,omitempty"`
-	Items           []domain.OrderItem      `json:"items"`
-	EventType       string                 `json:"event_type"`
-	Timestamp       int64                  `json:"timestamp"`
-}
-
-func NewNATSPublisher(url string) (*NATSPublisher, error) {
-	nc, err := nats.Connect(url)
-	if err != nil {
-		return nil, err
-	}
-
-	js, err := nc.JetStream()
-	if err != nil {
-		return nil, err
-	}
-
-	// Create the stream if it doesn't exist
-	stream := &nats.StreamConfig{
-		Name:     "ORDERS",
-		Subjects: []string{"order.*", "order.status.*"},
-	}
-
-	if _, err := js.AddStream(stream); err != nil {
-		if err != nats.ErrStreamNameAlreadyInUse {
-			return nil, err
-		}
-	}
-
-	return &NATSPublisher{
-		nc: nc,
-		js: js,
-	}, nil
-}
-
-func (p *NATSPublisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
-	event := OrderEvent{
-		ID:              order.ID,
-		UserID:          order.UserID,
-		Status:          string(order.Status),
-		TotalPrice:      order.TotalPrice,
-		Currency:        order.Currency,
-		DeliveryAddress: order.DeliveryAddress,
-		Items:           order.Items,
-		EventType:       "OrderCreated",
-		Timestamp:       order.CreatedAt.Unix(),
-	}
-
-	data, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	_, err = p.js.Publish(OrderCreatedSubject, data)
-	return err
-}
-
-func (p *NATSPublisher) PublishOrderStatusUpdated(ctx context.Context, order *domain.Order) error {
-	event := OrderEvent{
-		ID:              order.ID,
-		UserID:          order.UserID,
-		Status:          string(order.Status),
-		TotalPrice:      order.TotalPrice,
-		Currency:        order.Currency,
-		DeliveryAddress: order.DeliveryAddress,
-		Items:           order.Items,
-		EventType:       "OrderStatusUpdated",
-		Timestamp:       order.UpdatedAt.Unix(),
-	}
-
-	data, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	_, err = p.js.Publish(OrderStatusUpdatedSubject, data)
-	return err
-}
-
-func (p *NATSPublisher) PublishOrderCancelled(ctx context.Context, order *domain.Order) error {
-	event := OrderEvent{
-		ID:              order.ID,
-		UserID:          order.UserID,
-		Status:          string(order.Status),
-		TotalPrice:      order.TotalPrice,
-		Currency:        order.Currency,
-		DeliveryAddress: order.DeliveryAddress,
-		Items:           order.Items,
-		EventType:       "OrderCancelled",
-		Timestamp:       order.UpdatedAt.Unix(),
-	}
-
-	data, err := json.Marshal(event)
-	if err != nil {
-		return err
-	}
-
-	_, err = p.js.Publish(OrderCancelledSubject, data)
-	return err
-}
-
-func (p *NATSPublisher) Close() error {
-	p.nc.Close()
-	return nil
-} 
\ No newline at end of file
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/nats-io/nats.go"
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"github.com/hsibAD/order-service/internal/metrics"
+	"github.com/hsibAD/order-service/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+const (
+	OrderCreatedSubject       = "order.created"
+	OrderStatusUpdatedSubject = "order.status.updated"
+	OrderCancelledSubject     = "order.cancelled"
+	CourierAssignedSubject    = "order.courier.assigned"
+	UserDataErasedSubject     = "order.user.erased"
+	SlotHoldExpiredSubject    = "order.slot.hold_expired"
+)
+
+// OrderIDHeader names the order an event is about, so readers of the stream
+// can pick one order's events without decoding every payload.
+const OrderIDHeader = "Order-Id"
+
+// orderStream is the JetStream stream holding all events.
+const orderStream = "ORDERS"
+
+type NATSPublisher struct {
+	nc       *nats.Conn
+	js       nats.JetStreamContext
+	profiles PayloadProfiles
+}
+
+type OrderEvent struct {
+	ID              string                 `json:"id"`
+	UserID          string                 `json:"user_id"`
+	Status          string                 `json:"status"`
+	TotalPrice      float64               `json:"total_price"`
+	Currency        string                 `json:"currency"`
+	DeliveryAddress *domain.DeliveryAddress `json:"delivery_address,omitempty"`
+	DeliveryLocation *EventLocation         `json:"delivery_location,omitempty"`
+	Items           []domain.OrderItem      `json:"items"`
+	CourierID       string                 `json:"courier_id,omitempty"`
+	EventType       string                 `json:"event_type"`
+	Timestamp       int64                  `json:"timestamp"`
+}
+
+// EventLocation carries the geocoded delivery point for routing consumers.
+type EventLocation struct {
+	Latitude  float64 `json:"lat"`
+	Longitude float64 `json:"lng"`
+}
+
+type UserDataErasedEvent struct {
+	UserID    string   `json:"user_id"`
+	OrderIDs  []string `json:"order_ids"`
+	EventType string   `json:"event_type"`
+	Timestamp int64    `json:"timestamp"`
+}
+
+type SlotHoldExpiredEvent struct {
+	Token     string `json:"token"`
+	SlotID    string `json:"slot_id"`
+	UserID    string `json:"user_id"`
+	ExpiresAt int64  `json:"expires_at"`
+	EventType string `json:"event_type"`
+	Timestamp int64  `json:"timestamp"`
+}
+
+func NewNATSPublisher(url string, profiles PayloadProfiles, opts ...nats.Option) (*NATSPublisher, error) {
+	nc, err := nats.Connect(url, opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	js, err := nc.JetStream()
+	if err != nil {
+		return nil, err
+	}
+
+	// Create the stream if it doesn't exist, or add subjects introduced
+	// since it was created
+	stream := &nats.StreamConfig{
+		Name:     orderStream,
+		Subjects: []string{"order.*", "order.status.*", "order.courier.*", "order.user.*", "order.slot.*"},
+	}
+
+	if _, err := js.AddStream(stream); err != nil {
+		if err != nats.ErrStreamNameAlreadyInUse {
+			return nil, err
+		}
+		if _, err := js.UpdateStream(stream); err != nil {
+			return nil, err
+		}
+	}
+
+	return &NATSPublisher{
+		nc:       nc,
+		js:       js,
+		profiles: profiles,
+	}, nil
+}
+
+func (p *NATSPublisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	profile := p.profiles.For(OrderCreatedSubject)
+	event := OrderEvent{
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Status:          string(order.Status),
+		TotalPrice:      order.TotalPrice,
+		Currency:        order.Currency,
+		DeliveryAddress: eventAddress(order.DeliveryAddress, profile),
+		DeliveryLocation: deliveryLocation(order, profile),
+		Items:           order.Items,
+		EventType:       "OrderCreated",
+		Timestamp:       order.CreatedAt.Unix(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publish(ctx, OrderCreatedSubject, order.ID, data)
+}
+
+func (p *NATSPublisher) PublishOrderStatusUpdated(ctx context.Context, order *domain.Order) error {
+	profile := p.profiles.For(OrderStatusUpdatedSubject)
+	event := OrderEvent{
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Status:          string(order.Status),
+		TotalPrice:      order.TotalPrice,
+		Currency:        order.Currency,
+		DeliveryAddress: eventAddress(order.DeliveryAddress, profile),
+		DeliveryLocation: deliveryLocation(order, profile),
+		Items:           order.Items,
+		CourierID:       order.CourierID,
+		EventType:       "OrderStatusUpdated",
+		Timestamp:       order.UpdatedAt.Unix(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publish(ctx, OrderStatusUpdatedSubject, order.ID, data)
+}
+
+func (p *NATSPublisher) PublishOrderCancelled(ctx context.Context, order *domain.Order) error {
+	profile := p.profiles.For(OrderCancelledSubject)
+	event := OrderEvent{
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Status:          string(order.Status),
+		TotalPrice:      order.TotalPrice,
+		Currency:        order.Currency,
+		DeliveryAddress: eventAddress(order.DeliveryAddress, profile),
+		DeliveryLocation: deliveryLocation(order, profile),
+		Items:           order.Items,
+		CourierID:       order.CourierID,
+		EventType:       "OrderCancelled",
+		Timestamp:       order.UpdatedAt.Unix(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publish(ctx, OrderCancelledSubject, order.ID, data)
+}
+
+func (p *NATSPublisher) PublishCourierAssigned(ctx context.Context, order *domain.Order) error {
+	profile := p.profiles.For(CourierAssignedSubject)
+	event := OrderEvent{
+		ID:              order.ID,
+		UserID:          order.UserID,
+		Status:          string(order.Status),
+		TotalPrice:      order.TotalPrice,
+		Currency:        order.Currency,
+		DeliveryAddress: eventAddress(order.DeliveryAddress, profile),
+		DeliveryLocation: deliveryLocation(order, profile),
+		Items:           order.Items,
+		CourierID:       order.CourierID,
+		EventType:       "CourierAssigned",
+		Timestamp:       order.UpdatedAt.Unix(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publish(ctx, CourierAssignedSubject, order.ID, data)
+}
+
+func (p *NATSPublisher) PublishUserDataErased(ctx context.Context, userID string, orderIDs []string) error {
+	event := UserDataErasedEvent{
+		UserID:    userID,
+		OrderIDs:  orderIDs,
+		EventType: "UserDataErased",
+		Timestamp: time.Now().Unix(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publish(ctx, UserDataErasedSubject, "", data)
+}
+
+func (p *NATSPublisher) PublishDeliverySlotHoldExpired(ctx context.Context, hold *domain.SlotHold) error {
+	event := SlotHoldExpiredEvent{
+		Token:     hold.Token,
+		SlotID:    hold.SlotID,
+		UserID:    hold.UserID,
+		ExpiresAt: hold.ExpiresAt.Unix(),
+		EventType: "DeliverySlotHoldExpired",
+		Timestamp: time.Now().Unix(),
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	return p.publish(ctx, SlotHoldExpiredSubject, "", data)
+}
+
+// publish sends data with the trace context in the message headers, so
+// consumers can continue the trace with tracing.ExtractNATS, and with the
+// order's ID in OrderIDHeader when orderID is set.
+func (p *NATSPublisher) publish(ctx context.Context, subject, orderID string, data []byte) (err error) {
+	ctx, span := tracing.Start(ctx, "publish "+subject,
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(
+			attribute.String("messaging.system", "nats"),
+			attribute.String("messaging.destination.name", subject),
+		))
+	defer func() { tracing.End(span, err) }()
+
+	msg := nats.NewMsg(subject)
+	msg.Data = data
+	if orderID != "" {
+		msg.Header.Set(OrderIDHeader, orderID)
+	}
+	tracing.InjectNATS(ctx, msg)
+
+	if _, err := p.js.PublishMsg(msg); err != nil {
+		metrics.EventsPublished.WithLabelValues(subject, "failure").Inc()
+		logging.FromContext(ctx).Error("failed to publish event", zap.String("subject", subject), zap.Error(err))
+		return err
+	}
+	metrics.EventsPublished.WithLabelValues(subject, "success").Inc()
+	logging.FromContext(ctx).Debug("published event", zap.String("subject", subject), zap.Int("bytes", len(data)))
+	return nil
+}
+
+// PurgeOrderEvents removes every event of orderID from the ORDERS stream,
+// so no copy of its delivery address outlives an erasure. Events share
+// their subjects with other orders, so the stream's headers are scanned for
+// the order's ID. Consumers that haven't read the events yet miss them.
+func (p *NATSPublisher) PurgeOrderEvents(ctx context.Context, orderID string) error {
+	info, err := p.js.StreamInfo(orderStream, nats.Context(ctx))
+	if err != nil {
+		return fmt.Errorf("failed to get order stream info: %v", err)
+	}
+	if info.State.Msgs == 0 {
+		return nil
+	}
+
+	sub, err := p.js.SubscribeSync("", nats.BindStream(orderStream), nats.OrderedConsumer(), nats.DeliverAll(), nats.HeadersOnly())
+	if err != nil {
+		return fmt.Errorf("failed to scan order stream: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	for {
+		msg, err := sub.NextMsgWithContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to scan order stream: %v", err)
+		}
+		meta, err := msg.Metadata()
+		if err != nil {
+			return fmt.Errorf("failed to read order event metadata: %v", err)
+		}
+
+		if msg.Header.Get(OrderIDHeader) == orderID {
+			err := p.js.DeleteMsg(orderStream, meta.Sequence.Stream, nats.Context(ctx))
+			if err != nil && !errors.Is(err, nats.ErrMsgNotFound) {
+				return fmt.Errorf("failed to delete order event %d: %v", meta.Sequence.Stream, err)
+			}
+		}
+		// Events published after the scan started are left to later purges
+		if meta.NumPending == 0 || meta.Sequence.Stream >= info.State.LastSeq {
+			return nil
+		}
+	}
+}
+
+// Check reports whether the NATS connection is up, for readiness probes.
+// While reconnecting, publishes are buffered but may be lost.
+func (p *NATSPublisher) Check(ctx context.Context) error {
+	if status := p.nc.Status(); status != nats.CONNECTED {
+		return fmt.Errorf("nats connection is %s", status)
+	}
+	return nil
+}
+
+func (p *NATSPublisher) Close() error {
+	p.nc.Close()
+	return nil
+}
+
+// Drain flushes pending publishes and closes the connection, waiting until
+// it is closed or ctx is done.
+func (p *NATSPublisher) Drain(ctx context.Context) error {
+	if err := p.nc.Drain(); err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(50 * time.Millisecond)
+	defer ticker.Stop()
+	for !p.nc.IsClosed() {
+		select {
+		case <-ctx.Done():
+			p.nc.Close()
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// deliveryLocation pinpoints the building, so only full payloads carry it.
+func deliveryLocation(order *domain.Order, profile PayloadProfile) *EventLocation {
+	if profile != ProfileFull || order.DeliveryAddress == nil || order.DeliveryAddress.Location == nil {
+		return nil
+	}
+	return &EventLocation{
+		Latitude:  order.DeliveryAddress.Location.Latitude,
+		Longitude: order.DeliveryAddress.Location.Longitude,
+	}
+}
\ No newline at end of file
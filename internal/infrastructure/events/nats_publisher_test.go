@@ -0,0 +1,205 @@
+package events
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/nats-io/nats-server/v2/server"
+	"github.com/nats-io/nats.go"
+)
+
+func testOrder() *domain.Order {
+	return &domain.Order{
+		ID:         "64b7f0c2e13a4d5f6a7b8c9d",
+		UserID:     "user-1",
+		Items:      []domain.OrderItem{{ProductID: "p1", ProductName: "Milk", Quantity: 1, UnitPrice: 3, TotalPrice: 3}},
+		TotalPrice: 3,
+		Currency:   "GBP",
+		Status:     domain.OrderStatusCreated,
+		DeliveryAddress: &domain.DeliveryAddress{
+			UserID:        "user-1",
+			FullName:      "Jane Roe",
+			StreetAddress: "221B Baker Street",
+			City:          "London",
+			PostalCode:    "NW1 6XE",
+			Country:       "GB",
+			Phone:         "+44 20 7946 0958",
+			Location:      &domain.GeoPoint{Latitude: 51.5237, Longitude: -0.1585},
+			Preferences:   domain.DeliveryPreferences{AccessCode: "4711#"},
+		},
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+}
+
+// runPublisher connects a publisher to an in-process JetStream server.
+func runPublisher(t *testing.T, profiles PayloadProfiles) (*NATSPublisher, nats.JetStreamContext) {
+	t.Helper()
+
+	ns, err := server.NewServer(&server.Options{Port: -1, JetStream: true, StoreDir: t.TempDir()})
+	if err != nil {
+		t.Fatal(err)
+	}
+	go ns.Start()
+	t.Cleanup(ns.Shutdown)
+	if !ns.ReadyForConnections(5 * time.Second) {
+		t.Fatal("nats server not ready")
+	}
+
+	publisher, err := NewNATSPublisher(ns.ClientURL(), profiles)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { publisher.Close() })
+	return publisher, publisher.js
+}
+
+func lastPayload(t *testing.T, js nats.JetStreamContext, subject string) string {
+	t.Helper()
+	msg, err := js.GetLastMsg(orderStream, subject)
+	if err != nil {
+		t.Fatalf("no event on %s: %v", subject, err)
+	}
+	return string(msg.Data)
+}
+
+func TestEventPayloadProfiles(t *testing.T) {
+	profiles, err := NewPayloadProfiles("minimal", map[string]string{OrderCancelledSubject: "full"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	publisher, js := runPublisher(t, profiles)
+	ctx := context.Background()
+	order := testOrder()
+
+	if err := publisher.PublishOrderCreated(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.PublishOrderCancelled(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+
+	minimal := lastPayload(t, js, OrderCreatedSubject)
+	for _, value := range []string{"Jane Roe", "221B Baker Street", "+44 20 7946 0958", "4711#", "NW1 6XE", "51.5237"} {
+		if strings.Contains(minimal, value) {
+			t.Errorf("minimal event contains %q: %s", value, minimal)
+		}
+	}
+	if !strings.Contains(minimal, `"city":"London"`) && !strings.Contains(minimal, `"City":"London"`) {
+		t.Errorf("minimal event lost the locality: %s", minimal)
+	}
+
+	full := lastPayload(t, js, OrderCancelledSubject)
+	if !strings.Contains(full, "221B Baker Street") {
+		t.Errorf("full event lacks the street: %s", full)
+	}
+	if strings.Contains(full, "4711#") {
+		t.Errorf("full event contains the access code: %s", full)
+	}
+}
+
+// storedOrderIDs lists the OrderIDHeader of every event in the stream.
+func storedOrderIDs(t *testing.T, js nats.JetStreamContext) []string {
+	t.Helper()
+	info, err := js.StreamInfo(orderStream)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var ids []string
+	for seq := info.State.FirstSeq; seq <= info.State.LastSeq; seq++ {
+		msg, err := js.GetMsg(orderStream, seq)
+		if err == nats.ErrMsgNotFound {
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, msg.Header.Get(OrderIDHeader))
+	}
+	return ids
+}
+
+func TestPurgeOrderEvents(t *testing.T) {
+	publisher, js := runPublisher(t, PayloadProfiles{Default: ProfileFull})
+	ctx := context.Background()
+	order := testOrder()
+	other := testOrder()
+	other.ID = "64b7f0c2e13a4d5f6a7b8c9e"
+
+	for _, o := range []*domain.Order{order, other, order} {
+		if err := publisher.PublishOrderCreated(ctx, o); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := publisher.PublishOrderCancelled(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.PurgeOrderEvents(ctx, order.ID); err != nil {
+		t.Fatal(err)
+	}
+
+	if ids := storedOrderIDs(t, js); len(ids) != 1 || ids[0] != other.ID {
+		t.Errorf("stored events of %q, want only %s", ids, other.ID)
+	}
+	if err := publisher.PurgeOrderEvents(ctx, "64b7f0c2e13a4d5f6a7b8c9f"); err != nil {
+		t.Errorf("purging an order without events: %v", err)
+	}
+}
+
+func TestOrderFeedWatchesOneOrder(t *testing.T) {
+	publisher, _ := runPublisher(t, PayloadProfiles{Default: ProfileMinimal})
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	order := testOrder()
+	other := testOrder()
+	other.ID = "64b7f0c2e13a4d5f6a7b8c9e"
+
+	if err := publisher.PublishOrderCreated(ctx, order); err != nil {
+		t.Fatal(err)
+	}
+	if err := publisher.PublishOrderCreated(ctx, other); err != nil {
+		t.Fatal(err)
+	}
+
+	// Resuming after the first event replays the rest of the order's events
+	feed := NewOrderFeed(publisher)
+	start, changes, err := feed.Watch(ctx, order.ID, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if start != 1 {
+		t.Errorf("started from %d, want 1", start)
+	}
+
+	for _, publish := range []func(context.Context, *domain.Order) error{publisher.PublishOrderStatusUpdated, publisher.PublishOrderCancelled} {
+		if err := publish(ctx, other); err != nil {
+			t.Fatal(err)
+		}
+		if err := publish(ctx, order); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	for _, want := range []domain.OrderChange{
+		{OrderID: order.ID, Version: 4, Event: "OrderStatusUpdated"},
+		{OrderID: order.ID, Version: 6, Event: "OrderCancelled"},
+	} {
+		select {
+		case change := <-changes:
+			if change != want {
+				t.Errorf("got change %+v, want %+v", change, want)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("no change, want %+v", want)
+		}
+	}
+	select {
+	case change := <-changes:
+		t.Errorf("unexpected change %+v", change)
+	case <-time.After(100 * time.Millisecond):
+	}
+}
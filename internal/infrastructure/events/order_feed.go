@@ -0,0 +1,76 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"github.com/nats-io/nats.go"
+	"go.uber.org/zap"
+)
+
+// OrderFeed reports the order events published by any replica, read back
+// from the ORDERS stream. Versions are stream sequence numbers, so a watcher
+// can resume where it left off while the stream still holds that history.
+type OrderFeed struct {
+	js nats.JetStreamContext
+}
+
+func NewOrderFeed(publisher *NATSPublisher) *OrderFeed {
+	return &OrderFeed{js: publisher.js}
+}
+
+// Watch streams the events published for orderID after version since until
+// ctx is done, picked from the stream by their OrderIDHeader. It starts from
+// the latest version instead when since is 0 or no longer in the stream, and
+// returns the version it started from. The channel is never closed.
+func (f *OrderFeed) Watch(ctx context.Context, orderID string, since uint64) (uint64, <-chan domain.OrderChange, error) {
+	info, err := f.js.StreamInfo(orderStream)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to get order stream info: %v", err)
+	}
+
+	start := since
+	if since == 0 || since+1 < info.State.FirstSeq || since > info.State.LastSeq {
+		start = info.State.LastSeq
+	}
+
+	changes := make(chan domain.OrderChange, 16)
+	sub, err := f.js.Subscribe("", func(msg *nats.Msg) {
+		if msg.Header.Get(OrderIDHeader) != orderID {
+			return
+		}
+
+		var event struct {
+			EventType string `json:"event_type"`
+		}
+		if err := json.Unmarshal(msg.Data, &event); err != nil {
+			logging.FromContext(ctx).Warn("failed to decode order event", zap.String("subject", msg.Subject), zap.Error(err))
+			return
+		}
+		meta, err := msg.Metadata()
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to read order event metadata", zap.Error(err))
+			return
+		}
+
+		select {
+		case changes <- domain.OrderChange{OrderID: orderID, Version: meta.Sequence.Stream, Event: event.EventType}:
+		case <-ctx.Done():
+		}
+	}, nats.BindStream(orderStream), nats.OrderedConsumer(), nats.StartSequence(start+1))
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to subscribe to order events: %v", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		if err := sub.Unsubscribe(); err != nil && err != nats.ErrConnectionClosed {
+			logging.FromContext(ctx).Warn("failed to unsubscribe from order events", zap.Error(err))
+		}
+	}()
+
+	return start, changes, nil
+}
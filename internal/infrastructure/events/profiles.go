@@ -0,0 +1,77 @@
+package events
+
+import (
+	"fmt"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// PayloadProfile controls how much personal data an event carries.
+type PayloadProfile string
+
+const (
+	// ProfileFull includes the whole delivery address, for consumers that
+	// deliver the order. Access codes are never published.
+	ProfileFull PayloadProfile = "full"
+	// ProfileMinimal keeps only the locality of the address.
+	ProfileMinimal PayloadProfile = "minimal"
+)
+
+// PayloadProfiles picks the profile per NATS subject.
+type PayloadProfiles struct {
+	Default  PayloadProfile
+	Subjects map[string]PayloadProfile
+}
+
+// NewPayloadProfiles validates profile names from configuration.
+func NewPayloadProfiles(defaultProfile string, subjects map[string]string) (PayloadProfiles, error) {
+	profiles := PayloadProfiles{
+		Default:  PayloadProfile(defaultProfile),
+		Subjects: make(map[string]PayloadProfile, len(subjects)),
+	}
+	if !profiles.Default.valid() {
+		return PayloadProfiles{}, fmt.Errorf("unknown event payload profile %q", defaultProfile)
+	}
+
+	for subject, name := range subjects {
+		profile := PayloadProfile(name)
+		if !profile.valid() {
+			return PayloadProfiles{}, fmt.Errorf("unknown event payload profile %q for %s", name, subject)
+		}
+		profiles.Subjects[subject] = profile
+	}
+	return profiles, nil
+}
+
+func (p PayloadProfiles) For(subject string) PayloadProfile {
+	if profile, ok := p.Subjects[subject]; ok {
+		return profile
+	}
+	return p.Default
+}
+
+func (p PayloadProfile) valid() bool {
+	return p == ProfileFull || p == ProfileMinimal
+}
+
+// eventAddress shapes the delivery address for the profile. The minimal form
+// keeps the same fields with the personal ones left empty, so consumers see
+// one schema.
+func eventAddress(address *domain.DeliveryAddress, profile PayloadProfile) *domain.DeliveryAddress {
+	if address == nil {
+		return nil
+	}
+
+	if profile == ProfileFull {
+		return address.WithoutAccessCode()
+	}
+
+	return &domain.DeliveryAddress{
+		ID:         address.ID,
+		UserID:     address.UserID,
+		City:       address.City,
+		State:      address.State,
+		PostalCode: domain.RedactPostalCode(address.PostalCode),
+		Country:    address.Country,
+	}
+}
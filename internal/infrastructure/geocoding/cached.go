@@ -0,0 +1,50 @@
+package geocoding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+type Cache interface {
+	GetGeocode(ctx context.Context, key string) (*domain.GeoPoint, bool, error)
+	SetGeocode(ctx context.Context, key string, point *domain.GeoPoint, ttl time.Duration) error
+}
+
+// CachedGeocoder remembers results by normalized address. Misses are not
+// cached, so addresses the provider learns about later resolve eventually.
+type CachedGeocoder struct {
+	next  domain.Geocoder
+	cache Cache
+	ttl   time.Duration
+}
+
+func NewCachedGeocoder(next domain.Geocoder, cache Cache, ttl time.Duration) *CachedGeocoder {
+	return &CachedGeocoder{
+		next:  next,
+		cache: cache,
+		ttl:   ttl,
+	}
+}
+
+func (g *CachedGeocoder) Geocode(ctx context.Context, address *domain.DeliveryAddress) (*domain.GeoPoint, error) {
+	// Hashed so addresses don't show up in Redis key listings
+	sum := sha256.Sum256([]byte(Key(address.GeocodeQuery())))
+	key := hex.EncodeToString(sum[:])
+
+	// A cache outage falls through to the provider
+	if point, ok, err := g.cache.GetGeocode(ctx, key); err == nil && ok {
+		return point, nil
+	}
+
+	point, err := g.next.Geocode(ctx, address)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = g.cache.SetGeocode(ctx, key, point, g.ttl)
+	return point, nil
+}
@@ -0,0 +1,82 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// HTTPGeocoder calls a search endpoint speaking the Nominatim JSON format,
+// which most hosted geocoders offer as a compatibility API.
+type HTTPGeocoder struct {
+	baseURL string
+	apiKey  string
+	client  *http.Client
+}
+
+type searchResult struct {
+	Lat string `json:"lat"`
+	Lon string `json:"lon"`
+}
+
+func NewHTTPGeocoder(baseURL string, apiKey string, timeout time.Duration) *HTTPGeocoder {
+	return &HTTPGeocoder{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		apiKey:  apiKey,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (g *HTTPGeocoder) Geocode(ctx context.Context, address *domain.DeliveryAddress) (*domain.GeoPoint, error) {
+	params := url.Values{}
+	params.Set("format", "json")
+	params.Set("limit", "1")
+	params.Set("q", address.GeocodeQuery())
+	params.Set("countrycodes", strings.ToLower(address.Country))
+	if g.apiKey != "" {
+		params.Set("key", g.apiKey)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, g.baseURL+"/search?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	req.Header.Set("User-Agent", "order-service")
+
+	resp, err := g.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call geocoder: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("geocoder returned status %d", resp.StatusCode)
+	}
+
+	var results []searchResult
+	if err := json.NewDecoder(resp.Body).Decode(&results); err != nil {
+		return nil, fmt.Errorf("failed to decode geocoder response: %v", err)
+	}
+	if len(results) == 0 {
+		return nil, domain.ErrLocationNotFound
+	}
+
+	lat, err := strconv.ParseFloat(results[0].Lat, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid latitude from geocoder: %v", err)
+	}
+	lon, err := strconv.ParseFloat(results[0].Lon, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid longitude from geocoder: %v", err)
+	}
+
+	return &domain.GeoPoint{Latitude: lat, Longitude: lon}, nil
+}
@@ -0,0 +1,98 @@
+package geocoding
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// StaticGeocoder answers from a fixed table, for offline development and
+// tests. Exact addresses win over postal code centroids.
+type StaticGeocoder struct {
+	addresses   map[string]domain.GeoPoint
+	postalCodes map[string]domain.GeoPoint
+}
+
+// locationFile is the on-disk format:
+//
+//	{"locations": [
+//	    {"address": "10 Downing Street, London, SW1A 2AA, GB", "latitude": 51.5034, "longitude": -0.1276},
+//	    {"postal_code": "EC1A 1BB", "country": "GB", "latitude": 51.5202, "longitude": -0.0979}
+//	]}
+type locationFile struct {
+	Locations []struct {
+		Address    string  `json:"address"`
+		PostalCode string  `json:"postal_code"`
+		Country    string  `json:"country"`
+		Latitude   float64 `json:"latitude"`
+		Longitude  float64 `json:"longitude"`
+	} `json:"locations"`
+}
+
+func NewStaticGeocoder() *StaticGeocoder {
+	return &StaticGeocoder{
+		addresses:   make(map[string]domain.GeoPoint),
+		postalCodes: make(map[string]domain.GeoPoint),
+	}
+}
+
+// LoadFile builds a StaticGeocoder from a JSON locations file.
+func LoadFile(path string) (*StaticGeocoder, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read geocoder file: %v", err)
+	}
+
+	var file locationFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse geocoder file: %v", err)
+	}
+
+	geocoder := NewStaticGeocoder()
+	for _, location := range file.Locations {
+		point := domain.GeoPoint{Latitude: location.Latitude, Longitude: location.Longitude}
+		switch {
+		case location.Address != "":
+			geocoder.AddAddress(location.Address, point)
+		case location.PostalCode != "":
+			geocoder.AddPostalCode(location.PostalCode, location.Country, point)
+		default:
+			return nil, fmt.Errorf("geocoder location needs an address or postal code")
+		}
+	}
+
+	return geocoder, nil
+}
+
+// AddAddress registers a location for a single-line address as returned by
+// DeliveryAddress.GeocodeQuery.
+func (g *StaticGeocoder) AddAddress(query string, point domain.GeoPoint) {
+	g.addresses[Key(query)] = point
+}
+
+func (g *StaticGeocoder) AddPostalCode(postalCode string, country string, point domain.GeoPoint) {
+	g.postalCodes[postalKey(postalCode, country)] = point
+}
+
+func (g *StaticGeocoder) Geocode(ctx context.Context, address *domain.DeliveryAddress) (*domain.GeoPoint, error) {
+	if point, ok := g.addresses[Key(address.GeocodeQuery())]; ok {
+		return &point, nil
+	}
+	if point, ok := g.postalCodes[postalKey(address.PostalCode, address.Country)]; ok {
+		return &point, nil
+	}
+	return nil, domain.ErrLocationNotFound
+}
+
+// Key normalizes a single-line address for lookups and cache keys.
+func Key(query string) string {
+	return strings.ToLower(strings.Join(strings.Fields(query), " "))
+}
+
+func postalKey(postalCode string, country string) string {
+	return strings.ToUpper(country) + ":" + strings.ToUpper(strings.ReplaceAll(postalCode, " ", ""))
+}
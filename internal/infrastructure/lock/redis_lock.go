@@ -0,0 +1,203 @@
+package lock
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+var (
+	ErrNotAcquired = errors.New("lock is held by another owner")
+	ErrLockLost    = errors.New("lock expired or was taken over")
+)
+
+// Acquire takes the lock and bumps its fencing counter in one step, so every
+// successful acquisition gets a strictly larger token than the one before.
+// The counter has no expiry; letting it lapse would restart tokens at 1.
+var acquireScript = redis.NewScript(`
+if redis.call("SET", KEYS[1], ARGV[1], "NX", "PX", ARGV[2]) then
+	return redis.call("INCR", KEYS[2])
+end
+return 0
+`)
+
+// Release and refresh only touch the key while we are still its owner.
+var releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+var refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+// Delay between attempts while waiting for a held lock
+const retryInterval = 50 * time.Millisecond
+
+// Locker hands out Redis-backed mutual exclusion across replicas.
+type Locker struct {
+	client    *redis.Client
+	namespace string
+	ttl       time.Duration
+}
+
+// Lock is a held lock. Token is the fencing token for this acquisition:
+// writers should pass it along so storage can reject a stale holder.
+type Lock struct {
+	locker *Locker
+	key    string
+	owner  string
+	Token  int64
+}
+
+func NewLocker(client *redis.Client, namespace string, ttl time.Duration) *Locker {
+	return &Locker{
+		client:    client,
+		namespace: namespace,
+		ttl:       ttl,
+	}
+}
+
+// TryAcquire makes a single attempt and returns ErrNotAcquired if the lock
+// is currently held.
+func (l *Locker) TryAcquire(ctx context.Context, key string) (*Lock, error) {
+	owner, err := newOwnerID()
+	if err != nil {
+		return nil, err
+	}
+
+	lockKey := l.key(key)
+	token, err := acquireScript.Run(ctx, l.client,
+		[]string{lockKey, lockKey + ":fence"},
+		owner, l.ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	if token == 0 {
+		return nil, ErrNotAcquired
+	}
+
+	return &Lock{
+		locker: l,
+		key:    lockKey,
+		owner:  owner,
+		Token:  token,
+	}, nil
+}
+
+// Acquire retries until the lock is taken or ctx is done.
+func (l *Locker) Acquire(ctx context.Context, key string) (*Lock, error) {
+	ticker := time.NewTicker(retryInterval)
+	defer ticker.Stop()
+
+	for {
+		lock, err := l.TryAcquire(ctx, key)
+		if !errors.Is(err, ErrNotAcquired) {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire lock %s: %w", key, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithLock runs fn while holding key. The lease is renewed in the background
+// for long-running work; if renewal fails, fn's context is cancelled and
+// ErrLockLost is returned.
+func (l *Locker) WithLock(ctx context.Context, key string, fn func(ctx context.Context, token int64) error) error {
+	lock, err := l.Acquire(ctx, key)
+	if err != nil {
+		return err
+	}
+	defer lock.Release(context.Background())
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	lost := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := lock.Refresh(ctx); err != nil && ctx.Err() == nil {
+					close(lost)
+					cancel()
+					return
+				}
+			}
+		}
+	}()
+
+	err = fn(ctx, lock.Token)
+
+	select {
+	case <-lost:
+		return ErrLockLost
+	default:
+		return err
+	}
+}
+
+// WithOrderLock serializes mutations of a single order across replicas.
+func (l *Locker) WithOrderLock(ctx context.Context, orderID string, fn func(ctx context.Context, token int64) error) error {
+	return l.WithLock(ctx, "order:"+orderID, fn)
+}
+
+// WithSlotLock serializes reservations against a single delivery slot.
+func (l *Locker) WithSlotLock(ctx context.Context, slotID string, fn func(ctx context.Context, token int64) error) error {
+	return l.WithLock(ctx, "slot:"+slotID, fn)
+}
+
+// Refresh extends the lease by the locker's TTL.
+func (lk *Lock) Refresh(ctx context.Context) error {
+	ok, err := refreshScript.Run(ctx, lk.locker.client,
+		[]string{lk.key},
+		lk.owner, lk.locker.ttl.Milliseconds(),
+	).Int64()
+	if err != nil {
+		return err
+	}
+
+	if ok == 0 {
+		return ErrLockLost
+	}
+	return nil
+}
+
+// Release frees the lock if it is still ours. Releasing a lock that already
+// expired is not an error.
+func (lk *Lock) Release(ctx context.Context) error {
+	return releaseScript.Run(ctx, lk.locker.client, []string{lk.key}, lk.owner).Err()
+}
+
+func (l *Locker) key(key string) string {
+	return l.namespace + ":lock:" + key
+}
+
+func newOwnerID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
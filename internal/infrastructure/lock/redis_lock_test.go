@@ -0,0 +1,198 @@
+package lock
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+)
+
+func newTestLocker(t *testing.T, ttl time.Duration) (*Locker, *miniredis.Miniredis) {
+	t.Helper()
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return NewLocker(client, "test", ttl), mr
+}
+
+func TestTryAcquireHeld(t *testing.T) {
+	locker, _ := newTestLocker(t, time.Minute)
+	ctx := context.Background()
+
+	first, err := locker.TryAcquire(ctx, "slot:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locker.TryAcquire(ctx, "slot:1"); !errors.Is(err, ErrNotAcquired) {
+		t.Fatalf("second acquire: got %v, want ErrNotAcquired", err)
+	}
+	if _, err := locker.TryAcquire(ctx, "slot:2"); err != nil {
+		t.Fatalf("other key: %v", err)
+	}
+
+	if err := first.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := locker.TryAcquire(ctx, "slot:1"); err != nil {
+		t.Fatalf("acquire after release: %v", err)
+	}
+}
+
+func TestContendedAcquire(t *testing.T) {
+	locker, _ := newTestLocker(t, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var (
+		inside int32
+		mu     sync.Mutex
+		tokens []int64
+		wg     sync.WaitGroup
+	)
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := locker.WithLock(ctx, "slot:1", func(ctx context.Context, token int64) error {
+				if n := atomic.AddInt32(&inside, 1); n != 1 {
+					t.Errorf("%d holders inside the lock", n)
+				}
+				mu.Lock()
+				tokens = append(tokens, token)
+				mu.Unlock()
+				time.Sleep(5 * time.Millisecond)
+				atomic.AddInt32(&inside, -1)
+				return nil
+			})
+			if err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(tokens) != 8 {
+		t.Fatalf("got %d acquisitions, want 8", len(tokens))
+	}
+	for i := 1; i < len(tokens); i++ {
+		if tokens[i] <= tokens[i-1] {
+			t.Errorf("fencing tokens not increasing: %v", tokens)
+		}
+	}
+}
+
+func TestAcquireGivesUpWithContext(t *testing.T) {
+	locker, _ := newTestLocker(t, time.Minute)
+	if _, err := locker.TryAcquire(context.Background(), "slot:1"); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*retryInterval)
+	defer cancel()
+	if _, err := locker.Acquire(ctx, "slot:1"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("got %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestReleaseByNonOwner(t *testing.T) {
+	locker, mr := newTestLocker(t, time.Second)
+	ctx := context.Background()
+
+	stale, err := locker.TryAcquire(ctx, "slot:1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	mr.FastForward(2 * time.Second)
+
+	current, err := locker.TryAcquire(ctx, "slot:1")
+	if err != nil {
+		t.Fatalf("acquire after expiry: %v", err)
+	}
+	if current.Token <= stale.Token {
+		t.Errorf("token after takeover %d, want more than %d", current.Token, stale.Token)
+	}
+
+	if err := stale.Release(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if !mr.Exists("test:lock:slot:1") {
+		t.Fatal("former owner released the current owner's lock")
+	}
+	if err := stale.Refresh(ctx); !errors.Is(err, ErrLockLost) {
+		t.Errorf("refresh by former owner: got %v, want ErrLockLost", err)
+	}
+	if _, err := locker.TryAcquire(ctx, "slot:1"); !errors.Is(err, ErrNotAcquired) {
+		t.Errorf("lock is free while held: %v", err)
+	}
+}
+
+func TestFencingTokensGrow(t *testing.T) {
+	locker, mr := newTestLocker(t, time.Minute)
+	ctx := context.Background()
+
+	var last int64
+	for i := 0; i < 3; i++ {
+		lock, err := locker.TryAcquire(ctx, "order:1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if lock.Token <= last {
+			t.Fatalf("token %d after %d", lock.Token, last)
+		}
+		last = lock.Token
+		if err := lock.Release(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// The counter outlives the lock key, so tokens don't restart
+	if ttl := mr.TTL("test:lock:order:1:fence"); ttl != 0 {
+		t.Errorf("fence counter expires in %v", ttl)
+	}
+}
+
+func TestLeaseRenewal(t *testing.T) {
+	const ttl = 300 * time.Millisecond
+	locker, mr := newTestLocker(t, ttl)
+
+	err := locker.WithLock(context.Background(), "slot:1", func(ctx context.Context, _ int64) error {
+		// Without renewal the lease would run out during this call
+		for i := 0; i < 4; i++ {
+			mr.FastForward(ttl / 2)
+			time.Sleep(ttl / 2)
+			if !mr.Exists("test:lock:slot:1") {
+				return errors.New("lease expired while held")
+			}
+		}
+		return ctx.Err()
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mr.Exists("test:lock:slot:1") {
+		t.Error("lock not released")
+	}
+}
+
+func TestWithLockReportsLostLease(t *testing.T) {
+	const ttl = 150 * time.Millisecond
+	locker, mr := newTestLocker(t, ttl)
+
+	err := locker.WithLock(context.Background(), "slot:1", func(ctx context.Context, _ int64) error {
+		mr.Del("test:lock:slot:1")
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * ttl):
+			return errors.New("context not cancelled after the lease was lost")
+		}
+	})
+	if !errors.Is(err, ErrLockLost) {
+		t.Fatalf("got %v, want ErrLockLost", err)
+	}
+}
@@ -0,0 +1,142 @@
+package zones
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// zoneFile is the on-disk format:
+//
+//	{"zones": [{
+//	    "id": "ldn-central",
+//	    "name": "Central London",
+//	    "country": "GB",
+//	    "postal_codes": ["SW1A 1AA"],
+//	    "postal_prefixes": ["EC", "WC"],
+//	    "area": {"type": "Polygon", "coordinates": [[[-0.2, 51.4], ...]]}
+//	}]}
+//
+// The optional area is a GeoJSON Polygon or MultiPolygon geometry with
+// [longitude, latitude] positions.
+type zoneFile struct {
+	Zones []zoneDefinition `json:"zones"`
+}
+
+type zoneDefinition struct {
+	ID             string    `json:"id"`
+	Name           string    `json:"name"`
+	Country        string    `json:"country"`
+	PostalCodes    []string  `json:"postal_codes"`
+	PostalPrefixes []string  `json:"postal_prefixes"`
+	Area           *geometry `json:"area"`
+}
+
+type geometry struct {
+	Type        string          `json:"type"`
+	Coordinates json.RawMessage `json:"coordinates"`
+}
+
+// LoadFile reads zone definitions from path. An empty path yields an empty
+// catalog, which accepts every address.
+func LoadFile(path string) (*domain.ZoneCatalog, error) {
+	if path == "" {
+		return domain.NewZoneCatalog(nil), nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read zones file: %v", err)
+	}
+
+	var file zoneFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse zones file: %v", err)
+	}
+
+	zones := make([]*domain.DeliveryZone, 0, len(file.Zones))
+	for _, def := range file.Zones {
+		if def.ID == "" {
+			return nil, fmt.Errorf("zone without id in %s", path)
+		}
+
+		areas, err := def.Area.polygons()
+		if err != nil {
+			return nil, fmt.Errorf("zone %s: %v", def.ID, err)
+		}
+
+		name := def.Name
+		if name == "" {
+			name = def.ID
+		}
+
+		zones = append(zones, &domain.DeliveryZone{
+			ID:             def.ID,
+			Name:           name,
+			Country:        def.Country,
+			PostalCodes:    def.PostalCodes,
+			PostalPrefixes: def.PostalPrefixes,
+			Areas:          areas,
+		})
+	}
+
+	return domain.NewZoneCatalog(zones), nil
+}
+
+func (g *geometry) polygons() ([]domain.Polygon, error) {
+	if g == nil {
+		return nil, nil
+	}
+
+	switch g.Type {
+	case "Polygon":
+		var rings [][][]float64
+		if err := json.Unmarshal(g.Coordinates, &rings); err != nil {
+			return nil, fmt.Errorf("invalid polygon: %v", err)
+		}
+		polygon, err := toPolygon(rings)
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Polygon{polygon}, nil
+
+	case "MultiPolygon":
+		var polygons [][][][]float64
+		if err := json.Unmarshal(g.Coordinates, &polygons); err != nil {
+			return nil, fmt.Errorf("invalid multipolygon: %v", err)
+		}
+		result := make([]domain.Polygon, 0, len(polygons))
+		for _, rings := range polygons {
+			polygon, err := toPolygon(rings)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, polygon)
+		}
+		return result, nil
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %q", g.Type)
+	}
+}
+
+func toPolygon(rings [][][]float64) (domain.Polygon, error) {
+	polygon := make(domain.Polygon, len(rings))
+	for i, ring := range rings {
+		if len(ring) < 4 {
+			return nil, fmt.Errorf("polygon ring needs at least 4 positions")
+		}
+
+		points := make([]domain.GeoPoint, len(ring))
+		for j, position := range ring {
+			if len(position) < 2 {
+				return nil, fmt.Errorf("position needs longitude and latitude")
+			}
+			points[j] = domain.GeoPoint{Longitude: position[0], Latitude: position[1]}
+		}
+		polygon[i] = points
+	}
+	return polygon, nil
+}
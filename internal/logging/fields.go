@@ -0,0 +1,17 @@
+package logging
+
+import (
+	"github.com/hsibAD/order-service/internal/domain"
+	"go.uber.org/zap"
+)
+
+// Order logs the redacted view of order, without the recipient's name,
+// street, phone or access code.
+func Order(order *domain.Order) zap.Field {
+	return zap.Any("order", order.Redacted())
+}
+
+// Address logs the redacted view of address.
+func Address(address *domain.DeliveryAddress) zap.Field {
+	return zap.Any("address", address.Redacted())
+}
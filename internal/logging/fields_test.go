@@ -0,0 +1,84 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+)
+
+var personalData = []string{"Jane Roe", "221B Baker Street", "+44 20 7946 0958", "4711#"}
+
+func testOrder() *domain.Order {
+	return &domain.Order{
+		ID:     "order-1",
+		UserID: "user-1",
+		Status: domain.OrderStatusCreated,
+		DeliveryAddress: &domain.DeliveryAddress{
+			UserID:        "user-1",
+			FullName:      "Jane Roe",
+			StreetAddress: "221B Baker Street",
+			City:          "London",
+			PostalCode:    "NW1 6XE",
+			Country:       "GB",
+			Phone:         "+44 20 7946 0958",
+			Preferences:   domain.DeliveryPreferences{AccessCode: "4711#"},
+		},
+	}
+}
+
+func bufferLogger() (*zap.Logger, *bytes.Buffer) {
+	var buf bytes.Buffer
+	core := zapcore.NewCore(zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig()), zapcore.AddSync(&buf), zap.DebugLevel)
+	return zap.New(core), &buf
+}
+
+func assertNoPersonalData(t *testing.T, logs string) {
+	t.Helper()
+	for _, value := range personalData {
+		if strings.Contains(logs, value) {
+			t.Errorf("logs contain %q: %s", value, logs)
+		}
+	}
+}
+
+func TestOrderAndAddressFields(t *testing.T) {
+	logger, buf := bufferLogger()
+	order := testOrder()
+
+	logger.Info("order", Order(order))
+	logger.Info("address", Address(order.DeliveryAddress))
+
+	logs := buf.String()
+	assertNoPersonalData(t, logs)
+	if !strings.Contains(logs, `"city":"London"`) || !strings.Contains(logs, `"id":"order-1"`) {
+		t.Errorf("logs lack the redacted order: %s", logs)
+	}
+}
+
+func TestRequestLogRedactsCollectedFields(t *testing.T) {
+	logger, buf := bufferLogger()
+	interceptor := UnaryServerInterceptor(logger, logger)
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/order.OrderService/CreateOrder"}
+	_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+		ctx = With(ctx, Order(testOrder()))
+		FromContext(ctx).Warn("slot reservation failed")
+		return nil, errors.New("reservation failed")
+	})
+	if err == nil {
+		t.Fatal("expected the handler error")
+	}
+
+	logs := buf.String()
+	assertNoPersonalData(t, logs)
+	if strings.Count(logs, `"order":`) != 2 {
+		t.Errorf("both the handler and the request log should carry the order: %s", logs)
+	}
+}
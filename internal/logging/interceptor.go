@@ -0,0 +1,108 @@
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/grpcstream"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// RequestIDHeader carries the request ID in both directions. A caller's ID
+// is kept so one request can be followed across services.
+const RequestIDHeader = "x-request-id"
+
+// UnaryServerInterceptor puts a request-scoped logger into the context and
+// logs every call once it completes. Successful calls go through sampled,
+// failures always through logger.
+func UnaryServerInterceptor(logger, sampled *zap.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestID := incomingRequestID(ctx)
+		grpc.SetHeader(ctx, metadata.Pairs(RequestIDHeader, requestID))
+
+		ctx, done := begin(ctx, logger, sampled, requestID, info.FullMethod)
+		resp, err := handler(ctx, req)
+		done(err)
+		return resp, err
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls,
+// logged once the stream ends.
+func StreamServerInterceptor(logger, sampled *zap.Logger) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		requestID := incomingRequestID(ss.Context())
+		ss.SetHeader(metadata.Pairs(RequestIDHeader, requestID))
+
+		ctx, done := begin(ss.Context(), logger, sampled, requestID, info.FullMethod)
+		err := handler(srv, grpcstream.WithContext(ss, ctx))
+		done(err)
+		return err
+	}
+}
+
+// begin prepares the context of a call and returns it with the function
+// that logs the call's outcome.
+func begin(ctx context.Context, logger, sampled *zap.Logger, requestID, method string) (context.Context, func(error)) {
+	start := time.Now()
+	ctx, s := newScope(ctx)
+	ctx = NewContext(ctx, logger.With(
+		zap.String("request_id", requestID),
+		zap.String("method", method),
+	))
+	if spanContext := trace.SpanContextFromContext(ctx); spanContext.IsValid() {
+		ctx = With(ctx, zap.String("trace_id", spanContext.TraceID().String()))
+	}
+
+	return ctx, func(err error) {
+		code := status.Code(err)
+		fields := append([]zap.Field{
+			zap.String("request_id", requestID),
+			zap.String("method", method),
+			zap.String("code", code.String()),
+			zap.Duration("latency", time.Since(start)),
+		}, s.collected()...)
+		if err != nil {
+			fields = append(fields, zap.String("error", status.Convert(err).Message()))
+		}
+
+		if code == codes.OK {
+			sampled.Info("request completed", fields...)
+		} else {
+			logger.Log(levelFor(code), "request failed", fields...)
+		}
+	}
+}
+
+// levelFor separates caller mistakes from failures of the service itself.
+func levelFor(code codes.Code) zapcore.Level {
+	switch code {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists,
+		codes.FailedPrecondition, codes.OutOfRange, codes.Unauthenticated,
+		codes.PermissionDenied, codes.Canceled:
+		return zapcore.InfoLevel
+	case codes.ResourceExhausted, codes.Aborted, codes.DeadlineExceeded:
+		return zapcore.WarnLevel
+	default:
+		return zapcore.ErrorLevel
+	}
+}
+
+func incomingRequestID(ctx context.Context) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(RequestIDHeader); len(values) > 0 && values[0] != "" && len(values[0]) <= 128 {
+		return values[0]
+	}
+
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+type Config struct {
+	Level  string // debug, info, warn or error
+	Format string // json or console
+
+	// Of the successful requests each second, the first SampleInitial are
+	// logged and every SampleThereafter-th after that. Zero logs all of them.
+	SampleInitial    int
+	SampleThereafter int
+}
+
+// New builds the service logger. The returned level changes the logger's
+// level at runtime, see zap.AtomicLevel.
+func New(cfg Config) (*zap.Logger, zap.AtomicLevel, error) {
+	level, err := zap.ParseAtomicLevel(cfg.Level)
+	if err != nil {
+		return nil, level, fmt.Errorf("invalid log level %q: %v", cfg.Level, err)
+	}
+
+	var zapCfg zap.Config
+	switch cfg.Format {
+	case "json", "":
+		zapCfg = zap.NewProductionConfig()
+	case "console":
+		zapCfg = zap.NewDevelopmentConfig()
+	default:
+		return nil, level, fmt.Errorf("unknown log format %q", cfg.Format)
+	}
+	zapCfg.Level = level
+	// Sampling is applied to request logs only, see Sampled
+	zapCfg.Sampling = nil
+	// Request failures are logged at error level and the trace adds nothing
+	zapCfg.DisableStacktrace = true
+	zapCfg.EncoderConfig.TimeKey = "time"
+	zapCfg.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	logger, err := zapCfg.Build()
+	return logger, level, err
+}
+
+// Sampled wraps logger so repeated messages are thinned out as described on
+// Config. It returns logger unchanged when sampling is disabled.
+func Sampled(logger *zap.Logger, cfg Config) *zap.Logger {
+	if cfg.SampleInitial <= 0 {
+		return logger
+	}
+	return logger.WithOptions(zap.WrapCore(func(core zapcore.Core) zapcore.Core {
+		return zapcore.NewSamplerWithOptions(core, time.Second, cfg.SampleInitial, cfg.SampleThereafter)
+	}))
+}
+
+type loggerKey struct{}
+
+// scope collects fields added while a request is handled, so the request
+// log written by the interceptor includes them.
+type scope struct {
+	mu     sync.Mutex
+	fields []zap.Field
+}
+
+type scopeKey struct{}
+
+// NewContext returns a copy of ctx carrying logger.
+func NewContext(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerKey{}, logger)
+}
+
+// FromContext returns the logger carried by ctx, or the global logger.
+func FromContext(ctx context.Context) *zap.Logger {
+	if logger, ok := ctx.Value(loggerKey{}).(*zap.Logger); ok {
+		return logger
+	}
+	return zap.L()
+}
+
+// With adds fields to the logger carried by ctx and to the request log of
+// the enclosing call.
+func With(ctx context.Context, fields ...zap.Field) context.Context {
+	if s, ok := ctx.Value(scopeKey{}).(*scope); ok {
+		s.mu.Lock()
+		s.fields = append(s.fields, fields...)
+		s.mu.Unlock()
+	}
+	return NewContext(ctx, FromContext(ctx).With(fields...))
+}
+
+func newScope(ctx context.Context) (context.Context, *scope) {
+	s := &scope{}
+	return context.WithValue(ctx, scopeKey{}, s), s
+}
+
+func (s *scope) collected() []zap.Field {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]zap.Field(nil), s.fields...)
+}
@@ -0,0 +1,80 @@
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Namespace prefixes every metric of the service.
+const Namespace = "order_service"
+
+// Registry holds every collector of the service, served by Handler.
+var Registry = prometheus.NewRegistry()
+
+var (
+	GRPCHandlingSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "grpc_server_handling_seconds",
+		Help:      "Latency of gRPC calls by method and status code.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	MongoOperationSeconds = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: Namespace,
+		Name:      "mongo_operation_seconds",
+		Help:      "Latency of MongoDB repository calls.",
+		Buckets:   []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+	}, []string{"repository", "method"})
+
+	CacheRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "cache_requests_total",
+		Help:      "Redis cache lookups by keyspace and result (hit, miss or error).",
+	}, []string{"keyspace", "result"})
+
+	EventsPublished = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "events_published_total",
+		Help:      "NATS publishes by subject and result (success or failure).",
+	}, []string{"subject", "result"})
+
+	OrdersCreated = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: Namespace,
+		Name:      "orders_created_total",
+		Help:      "Orders created by initial status and currency.",
+	}, []string{"status", "currency"})
+
+	SlotBooked = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "delivery_slot_booked",
+		Help:      "Orders and live holds in today's delivery slots, by slot start (UTC).",
+	}, []string{"slot"})
+
+	SlotUtilization = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: Namespace,
+		Name:      "delivery_slot_utilization_ratio",
+		Help:      "Booked share of capacity of today's delivery slots, by slot start (UTC).",
+	}, []string{"slot"})
+)
+
+func init() {
+	Registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		GRPCHandlingSeconds,
+		MongoOperationSeconds,
+		CacheRequests,
+		EventsPublished,
+		OrdersCreated,
+		SlotBooked,
+		SlotUtilization,
+	)
+}
+
+// Handler serves Registry in the Prometheus exposition format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{})
+}
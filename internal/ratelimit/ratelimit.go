@@ -0,0 +1,128 @@
+package ratelimit
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/auth"
+	"golang.org/x/time/rate"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// idleTimeout is how long a caller's bucket is kept after its last call. A
+// full bucket is refilled well within it at any sensible rate.
+const idleTimeout = 10 * time.Minute
+
+// Limiter throttles calls per caller: the authenticated user, or the client
+// address for anonymous calls. The limits can be changed while serving.
+type Limiter struct {
+	mu        sync.Mutex
+	limit     rate.Limit
+	burst     int
+	callers   map[string]*caller
+	lastPrune time.Time
+}
+
+type caller struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// New returns a Limiter allowing perMinute calls per caller with bursts of
+// up to burst calls. A perMinute of 0 disables limiting.
+func New(perMinute, burst int) *Limiter {
+	l := &Limiter{callers: make(map[string]*caller)}
+	l.SetLimits(perMinute, burst)
+	return l
+}
+
+// SetLimits changes the limits of every caller, including existing ones.
+func (l *Limiter) SetLimits(perMinute, burst int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.limit = rate.Limit(float64(perMinute) / 60)
+	l.burst = burst
+	for _, c := range l.callers {
+		c.limiter.SetLimit(l.limit)
+		c.limiter.SetBurst(l.burst)
+	}
+}
+
+// Allow reports whether key may make a call now.
+func (l *Limiter) Allow(key string) bool {
+	now := time.Now()
+
+	l.mu.Lock()
+	if l.limit == 0 {
+		l.mu.Unlock()
+		return true
+	}
+	c, ok := l.callers[key]
+	if !ok {
+		c = &caller{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.callers[key] = c
+	}
+	c.lastSeen = now
+	l.prune(now)
+	l.mu.Unlock()
+
+	return c.limiter.AllowN(now, 1)
+}
+
+// prune drops the buckets of callers idle for longer than idleTimeout, at
+// most once per idleTimeout. The caller holds l.mu.
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < idleTimeout {
+		return
+	}
+	for key, c := range l.callers {
+		if now.Sub(c.lastSeen) > idleTimeout {
+			delete(l.callers, key)
+		}
+	}
+	l.lastPrune = now
+}
+
+// UnaryServerInterceptor rejects calls over the limit with
+// ResourceExhausted. It must run after the auth interceptor so callers are
+// identified; health checks are never limited.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !strings.HasPrefix(info.FullMethod, "/grpc.health.v1.") && !l.Allow(callerKey(ctx)) {
+			return nil, status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor is UnaryServerInterceptor for streaming calls,
+// which count once when they are opened.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if !strings.HasPrefix(info.FullMethod, "/grpc.health.v1.") && !l.Allow(callerKey(ss.Context())) {
+			return status.Error(codes.ResourceExhausted, "rate limit exceeded")
+		}
+		return handler(srv, ss)
+	}
+}
+
+func callerKey(ctx context.Context) string {
+	if principal := auth.FromContext(ctx); principal != nil {
+		return "user:" + principal.UserID
+	}
+	if p, ok := peer.FromContext(ctx); ok {
+		// Ports differ between connections of the same client
+		addr := p.Addr.String()
+		if i := strings.LastIndexByte(addr, ':'); i > 0 {
+			addr = addr[:i]
+		}
+		return "addr:" + addr
+	}
+	return "anonymous"
+}
@@ -0,0 +1,169 @@
+package cached
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+	"golang.org/x/sync/singleflight"
+)
+
+// A shared load outlives the caller that started it, so it gets a deadline
+// of its own.
+const loadTimeout = 10 * time.Second
+
+// OrderCache is the cache surface the decorator relies on.
+type OrderCache interface {
+	GetOrder(ctx context.Context, orderID string) (*domain.Order, error)
+	// OrderGeneration is read before a load; SetOrder and SetOrderNotFound
+	// drop the fill when DeleteOrder ran since
+	OrderGeneration(ctx context.Context, orderID string) (int64, error)
+	SetOrder(ctx context.Context, order *domain.Order, generation int64, ttl time.Duration) error
+	SetOrderNotFound(ctx context.Context, orderID string, generation int64, ttl time.Duration) error
+	DeleteOrder(ctx context.Context, orderID string) error
+}
+
+// OrderRepository wraps another domain.OrderRepository with a read-through
+// cache. Reads of a single order go through the cache, every write drops the
+// cached copy, and concurrent misses for the same ID share one database call.
+type OrderRepository struct {
+	next        domain.OrderRepository
+	cache       OrderCache
+	ttl         time.Duration
+	negativeTTL time.Duration
+	group       singleflight.Group
+}
+
+// NewOrderRepository builds the decorator. negativeTTL controls how long
+// unknown IDs are remembered; zero disables negative caching.
+func NewOrderRepository(next domain.OrderRepository, cache OrderCache, ttl, negativeTTL time.Duration) *OrderRepository {
+	return &OrderRepository{
+		next:        next,
+		cache:       cache,
+		ttl:         ttl,
+		negativeTTL: negativeTTL,
+	}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) error {
+	if err := r.next.Create(ctx, order); err != nil {
+		return err
+	}
+
+	// Clear a possible negative entry for the new ID
+	return r.invalidate(ctx, order.ID)
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	order, err := r.cache.GetOrder(ctx, id)
+	if err == nil && order != nil {
+		return order, nil
+	}
+	if errors.Is(err, domain.ErrInvalidOrderID) {
+		return nil, err
+	}
+	if err != nil {
+		// Any other cache error degrades to a database read
+		logging.FromContext(ctx).Warn("order cache read failed", zap.String("order_id", id), zap.Error(err))
+	}
+
+	// The load is shared, so it must not fail because the caller that
+	// happened to start it gave up; each caller only stops waiting
+	flight := r.group.DoChan(id, func() (interface{}, error) {
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), loadTimeout)
+		defer cancel()
+		return r.load(ctx, id)
+	})
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-flight:
+		if result.Err != nil {
+			return nil, result.Err
+		}
+		// Callers sharing a flight must not see each other's mutations
+		return cloneOrder(result.Val.(*domain.Order)), nil
+	}
+}
+
+// load reads the order from the database and caches the outcome, unless a
+// write invalidated the order while it was being read.
+func (r *OrderRepository) load(ctx context.Context, id string) (*domain.Order, error) {
+	generation, genErr := r.cache.OrderGeneration(ctx, id)
+	if genErr != nil {
+		logging.FromContext(ctx).Warn("order cache read failed", zap.String("order_id", id), zap.Error(genErr))
+	}
+
+	order, err := r.next.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, domain.ErrInvalidOrderID) && r.negativeTTL > 0 && genErr == nil {
+			r.logCacheWrite(ctx, id, r.cache.SetOrderNotFound(ctx, id, generation, r.negativeTTL))
+		}
+		return nil, err
+	}
+
+	if genErr == nil {
+		r.logCacheWrite(ctx, id, r.cache.SetOrder(ctx, order, generation, r.ttl))
+	}
+	return order, nil
+}
+
+func (r *OrderRepository) GetByUserID(ctx context.Context, userID string, page, limit int) ([]*domain.Order, int, error) {
+	return r.next.GetByUserID(ctx, userID, page, limit)
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) error {
+	if err := r.next.Update(ctx, order); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, order.ID)
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID string, status domain.OrderStatus) error {
+	if err := r.next.UpdateStatus(ctx, orderID, status); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, orderID)
+}
+
+func (r *OrderRepository) Delete(ctx context.Context, id string) error {
+	if err := r.next.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, id)
+}
+
+// invalidate runs after every write. Loads already in flight may have read
+// the old order, so later readers must not join them.
+func (r *OrderRepository) invalidate(ctx context.Context, orderID string) error {
+	r.group.Forget(orderID)
+	if err := r.cache.DeleteOrder(ctx, orderID); err != nil {
+		return fmt.Errorf("failed to invalidate cached order %s: %v", orderID, err)
+	}
+	return nil
+}
+
+// logCacheWrite records a failed fill; the next read just misses again.
+func (r *OrderRepository) logCacheWrite(ctx context.Context, orderID string, err error) {
+	if err != nil {
+		logging.FromContext(ctx).Warn("order cache write failed", zap.String("order_id", orderID), zap.Error(err))
+	}
+}
+
+func cloneOrder(order *domain.Order) *domain.Order {
+	clone := *order
+	clone.Items = append([]domain.OrderItem(nil), order.Items...)
+	if order.DeliveryAddress != nil {
+		address := *order.DeliveryAddress
+		clone.DeliveryAddress = &address
+	}
+	return &clone
+}
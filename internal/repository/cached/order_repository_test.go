@@ -0,0 +1,140 @@
+package cached
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/infrastructure/cache"
+)
+
+type plainCipher struct{}
+
+func (plainCipher) Encrypt(plaintext string) (string, error)  { return plaintext, nil }
+func (plainCipher) Decrypt(ciphertext string) (string, error) { return ciphertext, nil }
+
+// blockingOrders is an in-memory order store whose first GetByID reads the
+// order and then waits for release before returning it.
+type blockingOrders struct {
+	domain.OrderRepository
+
+	mu      sync.Mutex
+	orders  map[string]domain.Order
+	reads   int
+	read    chan struct{}
+	release chan struct{}
+}
+
+func (r *blockingOrders) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	r.mu.Lock()
+	order, ok := r.orders[id]
+	r.reads++
+	first := r.reads == 1
+	r.mu.Unlock()
+
+	if first {
+		close(r.read)
+		<-r.release
+	}
+	if !ok {
+		return nil, domain.ErrInvalidOrderID
+	}
+	return &order, nil
+}
+
+func (r *blockingOrders) Create(ctx context.Context, order *domain.Order) error {
+	return r.Update(ctx, order)
+}
+
+func (r *blockingOrders) Update(ctx context.Context, order *domain.Order) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[order.ID] = *order
+	return nil
+}
+
+func newTestRepository(t *testing.T, orders *blockingOrders) (*OrderRepository, *cache.RedisCache) {
+	t.Helper()
+	server := miniredis.RunT(t)
+	redisCache := cache.NewRedisCache(cache.RedisConfig{Addr: server.Addr(), Namespace: "test", Cipher: plainCipher{}})
+	t.Cleanup(func() { redisCache.Close() })
+	return NewOrderRepository(orders, redisCache, time.Hour, time.Minute), redisCache
+}
+
+func TestLoadStartedBeforeUpdateIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	orders := &blockingOrders{
+		orders:  map[string]domain.Order{"order-1": {ID: "order-1", Status: domain.OrderStatusCreated}},
+		read:    make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	repo, redisCache := newTestRepository(t, orders)
+
+	stale := make(chan *domain.Order)
+	go func() {
+		order, _ := repo.GetByID(ctx, "order-1")
+		stale <- order
+	}()
+	<-orders.read
+
+	if err := repo.Update(ctx, &domain.Order{ID: "order-1", Status: domain.OrderStatusPaid}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A reader arriving after the write must not join the earlier load
+	order, err := repo.GetByID(ctx, "order-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if order.Status != domain.OrderStatusPaid {
+		t.Errorf("read after update = %s, want PAID", order.Status)
+	}
+
+	close(orders.release)
+	if order := <-stale; order == nil || order.Status != domain.OrderStatusCreated {
+		t.Fatalf("earlier load = %+v, want the CREATED order", order)
+	}
+
+	cached, err := redisCache.GetOrder(ctx, "order-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cached == nil || cached.Status != domain.OrderStatusPaid {
+		t.Errorf("cached order = %+v, want PAID", cached)
+	}
+}
+
+func TestNotFoundBeforeCreateIsNotCached(t *testing.T) {
+	ctx := context.Background()
+	orders := &blockingOrders{
+		orders:  map[string]domain.Order{},
+		read:    make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	repo, redisCache := newTestRepository(t, orders)
+
+	done := make(chan error)
+	go func() {
+		_, err := repo.GetByID(ctx, "order-1")
+		done <- err
+	}()
+	<-orders.read
+
+	if err := repo.Create(ctx, &domain.Order{ID: "order-1", Status: domain.OrderStatusCreated}); err != nil {
+		t.Fatal(err)
+	}
+	close(orders.release)
+	if err := <-done; err != domain.ErrInvalidOrderID {
+		t.Fatalf("earlier load = %v, want ErrInvalidOrderID", err)
+	}
+
+	if cached, err := redisCache.GetOrder(ctx, "order-1"); err != nil || cached != nil {
+		t.Errorf("cache = %+v, %v; want a miss", cached, err)
+	}
+	if _, err := repo.GetByID(ctx, "order-1"); err != nil {
+		t.Errorf("GetByID after create: %v", err)
+	}
+}
@@ -0,0 +1,437 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+type DeliveryAddressRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+	cipher     FieldCipher
+}
+
+func NewDeliveryAddressRepository(db *mongo.Database, cipher FieldCipher) *DeliveryAddressRepository {
+	return &DeliveryAddressRepository{
+		db:         db,
+		collection: db.Collection("delivery_addresses"),
+		cipher:     cipher,
+	}
+}
+
+func (r *DeliveryAddressRepository) Create(ctx context.Context, address *domain.DeliveryAddress) error {
+	defer observe("delivery_address", "Create")()
+
+	mAddress, err := r.toMongo(address)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.InsertOne(ctx, mAddress)
+	if err != nil {
+		return err
+	}
+
+	address.ID = result.InsertedID.(primitive.ObjectID).Hex()
+	return nil
+}
+
+func (r *DeliveryAddressRepository) GetByID(ctx context.Context, id string) (*domain.DeliveryAddress, error) {
+	defer observe("delivery_address", "GetByID")()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.ErrInvalidAddressID
+	}
+
+	var mAddress mongoDeliveryAddress
+	err = r.collection.FindOne(ctx, bson.M{"_id": objectID}).Decode(&mAddress)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvalidAddressID
+		}
+		return nil, err
+	}
+
+	return fromMongoDeliveryAddress(&mAddress, r.cipher)
+}
+
+func (r *DeliveryAddressRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.DeliveryAddress, error) {
+	defer observe("delivery_address", "GetByUserID")()
+
+	// ObjectIDs grow with insertion time, so this lists oldest first
+	opts := options.Find().SetSort(bson.M{"_id": 1})
+	cursor, err := r.collection.Find(ctx, bson.M{"user_id": userID}, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mAddresses []mongoDeliveryAddress
+	if err = cursor.All(ctx, &mAddresses); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*domain.DeliveryAddress, len(mAddresses))
+	for i := range mAddresses {
+		if addresses[i], err = fromMongoDeliveryAddress(&mAddresses[i], r.cipher); err != nil {
+			return nil, err
+		}
+	}
+
+	return addresses, nil
+}
+
+// GetByFingerprint lists the user's saved addresses with the given
+// fingerprint and phone, oldest first. Both are stored deterministically
+// encrypted, so they are matched under every key in the ring. Addresses
+// saved before fingerprints were stored are only found once
+// RotateEncryptionKeys has rewritten them.
+func (r *DeliveryAddressRepository) GetByFingerprint(ctx context.Context, userID string, fingerprint string, phone string) ([]*domain.DeliveryAddress, error) {
+	defer observe("delivery_address", "GetByFingerprint")()
+
+	fingerprints, err := r.cipher.DeterministicCandidates(fingerprint)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt fingerprint: %v", err)
+	}
+	phones, err := r.cipher.DeterministicCandidates(phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt phone: %v", err)
+	}
+
+	filter := bson.M{
+		"user_id":     userID,
+		"fingerprint": bson.M{"$in": fingerprints},
+		"phone":       bson.M{"$in": phones},
+	}
+	cursor, err := r.collection.Find(ctx, filter, options.Find().SetSort(bson.M{"_id": 1}))
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mAddresses []mongoDeliveryAddress
+	if err = cursor.All(ctx, &mAddresses); err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*domain.DeliveryAddress, len(mAddresses))
+	for i := range mAddresses {
+		if addresses[i], err = fromMongoDeliveryAddress(&mAddresses[i], r.cipher); err != nil {
+			return nil, err
+		}
+	}
+
+	return addresses, nil
+}
+
+func (r *DeliveryAddressRepository) Update(ctx context.Context, address *domain.DeliveryAddress) error {
+	defer observe("delivery_address", "Update")()
+
+	objectID, err := primitive.ObjectIDFromHex(address.ID)
+	if err != nil {
+		return domain.ErrInvalidAddressID
+	}
+
+	mAddress, err := r.toMongo(address)
+	if err != nil {
+		return err
+	}
+	mAddress.ID = objectID
+
+	result, err := r.collection.ReplaceOne(ctx, bson.M{"_id": objectID}, mAddress)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidAddressID
+	}
+
+	return nil
+}
+
+func (r *DeliveryAddressRepository) Delete(ctx context.Context, id string) error {
+	defer observe("delivery_address", "Delete")()
+
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return domain.ErrInvalidAddressID
+	}
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": objectID})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrInvalidAddressID
+	}
+
+	return nil
+}
+
+func (r *DeliveryAddressRepository) SetDefault(ctx context.Context, userID string, addressID string) error {
+	defer observe("delivery_address", "SetDefault")()
+
+	objectID, err := primitive.ObjectIDFromHex(addressID)
+	if err != nil {
+		return domain.ErrInvalidAddressID
+	}
+
+	// Clear the current default first so the user never ends up with two
+	_, err = r.collection.UpdateMany(ctx,
+		bson.M{"user_id": userID, "is_default": true},
+		bson.M{"$set": bson.M{"is_default": false}},
+	)
+	if err != nil {
+		return err
+	}
+
+	result, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": objectID, "user_id": userID},
+		bson.M{"$set": bson.M{"is_default": true}},
+	)
+	if err != nil {
+		return err
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.ErrInvalidAddressID
+	}
+
+	return nil
+}
+
+// ListUserIDsWithMultipleAddresses returns the users that could have
+// duplicate addresses.
+func (r *DeliveryAddressRepository) ListUserIDsWithMultipleAddresses(ctx context.Context) ([]string, error) {
+	defer observe("delivery_address", "ListUserIDsWithMultipleAddresses")()
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{"_id": "$user_id", "count": bson.M{"$sum": 1}}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var groups []struct {
+		UserID string `bson:"_id"`
+	}
+	if err = cursor.All(ctx, &groups); err != nil {
+		return nil, err
+	}
+
+	userIDs := make([]string, len(groups))
+	for i, group := range groups {
+		userIDs[i] = group.UserID
+	}
+
+	return userIDs, nil
+}
+
+// RotateEncryptionKeys rewrites addresses still encrypted under an older
+// master key, not encrypted at all or stored without a fingerprint, and
+// passes the owner of each rewritten address to invalidate. Only the
+// encrypted fields are written, and only while the address is as it was
+// read, so concurrent changes are kept. It returns the number rewritten.
+func (r *DeliveryAddressRepository) RotateEncryptionKeys(ctx context.Context, invalidate func(ctx context.Context, userID string) error) (int, error) {
+	cursor, err := r.collection.Find(ctx, bson.M{"$or": bson.A{
+		bson.M{"pii_key_id": bson.M{"$ne": r.cipher.ActiveKeyID()}},
+		bson.M{"fingerprint": bson.M{"$exists": false}},
+	}})
+	if err != nil {
+		return 0, err
+	}
+	defer cursor.Close(ctx)
+
+	rotated := 0
+	for cursor.Next(ctx) {
+		var mAddress mongoDeliveryAddress
+		if err := cursor.Decode(&mAddress); err != nil {
+			return rotated, err
+		}
+
+		address, err := fromMongoDeliveryAddress(&mAddress, r.cipher)
+		if err != nil {
+			return rotated, err
+		}
+		sealed, err := r.toMongo(address)
+		if err != nil {
+			return rotated, err
+		}
+
+		fields := bson.M{
+			"full_name":      sealed.FullName,
+			"street_address": sealed.StreetAddress,
+			"apartment":      sealed.Apartment,
+			"phone":          sealed.Phone,
+			"pii_key_id":     sealed.KeyID,
+			"fingerprint":    sealed.Fingerprint,
+		}
+		if sealed.Preferences != nil {
+			fields["preferences.access_code"] = sealed.Preferences.AccessCode
+		}
+
+		// Every write sets the active key and a fingerprint, so neither
+		// still matching means the address is unchanged
+		filter := bson.M{"_id": mAddress.ID, "pii_key_id": keyIDMatch(mAddress.KeyID)}
+		if mAddress.Fingerprint == "" {
+			filter["fingerprint"] = bson.M{"$exists": false}
+		} else {
+			filter["fingerprint"] = mAddress.Fingerprint
+		}
+
+		result, err := r.collection.UpdateOne(ctx, filter, bson.M{"$set": fields})
+		if err != nil {
+			return rotated, err
+		}
+		if result.MatchedCount == 0 {
+			continue
+		}
+		rotated++
+
+		if err := invalidate(ctx, address.UserID); err != nil {
+			return rotated, fmt.Errorf("failed to invalidate cached addresses: %v", err)
+		}
+	}
+
+	return rotated, cursor.Err()
+}
+
+// toMongo maps a saved address, which unlike order snapshots also stores
+// its fingerprint for GetByFingerprint.
+func (r *DeliveryAddressRepository) toMongo(address *domain.DeliveryAddress) (*mongoDeliveryAddress, error) {
+	mAddress, err := toMongoDeliveryAddress(address, r.cipher)
+	if err != nil {
+		return nil, err
+	}
+
+	if mAddress.Fingerprint, err = r.cipher.EncryptDeterministic(address.Fingerprint()); err != nil {
+		return nil, fmt.Errorf("failed to encrypt delivery address: %v", err)
+	}
+	return mAddress, nil
+}
+
+func toMongoDeliveryAddress(address *domain.DeliveryAddress, cipher FieldCipher) (*mongoDeliveryAddress, error) {
+	mAddress := &mongoDeliveryAddress{
+		UserID:        address.UserID,
+		FullName:      address.FullName,
+		StreetAddress: address.StreetAddress,
+		Apartment:     address.Apartment,
+		City:          address.City,
+		State:         address.State,
+		PostalCode:    address.PostalCode,
+		Country:       address.Country,
+		Phone:         address.Phone,
+		IsDefault:     address.IsDefault,
+	}
+
+	if address.Location != nil {
+		mAddress.Location = &mongoGeoPoint{
+			Type:        "Point",
+			Coordinates: []float64{address.Location.Longitude, address.Location.Latitude},
+		}
+	}
+
+	if address.Preferences != (domain.DeliveryPreferences{}) {
+		mAddress.Preferences = &mongoDeliveryPreferences{
+			Instructions:  address.Preferences.Instructions,
+			LeaveAtDoor:   address.Preferences.LeaveAtDoor,
+			Floor:         address.Preferences.Floor,
+			HasElevator:   address.Preferences.HasElevator,
+			ContactMethod: string(address.Preferences.ContactMethod),
+			AccessCode:    address.Preferences.AccessCode,
+		}
+	}
+
+	if err := encryptMongoDeliveryAddress(mAddress, cipher); err != nil {
+		return nil, err
+	}
+
+	if address.ID != "" {
+		if objectID, err := primitive.ObjectIDFromHex(address.ID); err == nil {
+			mAddress.ID = objectID
+		}
+	}
+
+	return mAddress, nil
+}
+
+func fromMongoDeliveryAddress(mAddress *mongoDeliveryAddress, cipher FieldCipher) (*domain.DeliveryAddress, error) {
+	var location *domain.GeoPoint
+	if mAddress.Location != nil && len(mAddress.Location.Coordinates) == 2 {
+		location = &domain.GeoPoint{
+			Latitude:  mAddress.Location.Coordinates[1],
+			Longitude: mAddress.Location.Coordinates[0],
+		}
+	}
+
+	var preferences domain.DeliveryPreferences
+	if p := mAddress.Preferences; p != nil {
+		preferences = domain.DeliveryPreferences{
+			Instructions:  p.Instructions,
+			LeaveAtDoor:   p.LeaveAtDoor,
+			Floor:         p.Floor,
+			HasElevator:   p.HasElevator,
+			ContactMethod: domain.ContactMethod(p.ContactMethod),
+			AccessCode:    p.AccessCode,
+		}
+	}
+
+	address := &domain.DeliveryAddress{
+		ID:            mAddress.ID.Hex(),
+		UserID:        mAddress.UserID,
+		FullName:      mAddress.FullName,
+		StreetAddress: mAddress.StreetAddress,
+		Apartment:     mAddress.Apartment,
+		City:          mAddress.City,
+		State:         mAddress.State,
+		PostalCode:    mAddress.PostalCode,
+		Country:       mAddress.Country,
+		Phone:         mAddress.Phone,
+		IsDefault:     mAddress.IsDefault,
+		Location:      location,
+		Preferences:   preferences,
+	}
+
+	// Documents without a key ID predate field encryption and hold the
+	// other fields in plaintext; access codes were always encrypted
+	fields := []*string{&address.Preferences.AccessCode}
+	if mAddress.KeyID != "" {
+		fields = append(fields, &address.FullName, &address.StreetAddress, &address.Apartment, &address.Phone)
+	}
+	if err := openFields(cipher, fields...); err != nil {
+		return nil, fmt.Errorf("failed to decrypt delivery address: %v", err)
+	}
+
+	return address, nil
+}
+
+func encryptMongoDeliveryAddress(mAddress *mongoDeliveryAddress, cipher FieldCipher) error {
+	fields := []*string{&mAddress.FullName, &mAddress.StreetAddress, &mAddress.Apartment}
+	if mAddress.Preferences != nil {
+		fields = append(fields, &mAddress.Preferences.AccessCode)
+	}
+
+	if err := sealFields(cipher.Encrypt, fields...); err != nil {
+		return fmt.Errorf("failed to encrypt delivery address: %v", err)
+	}
+	if err := sealFields(cipher.EncryptDeterministic, &mAddress.Phone); err != nil {
+		return fmt.Errorf("failed to encrypt delivery address: %v", err)
+	}
+
+	mAddress.KeyID = cipher.ActiveKeyID()
+	return nil
+}
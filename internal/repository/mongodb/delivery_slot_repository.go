@@ -0,0 +1,94 @@
+package mongodb
+
+import (
+	"context"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// DeliverySlotRepository tracks which orders booked each slot. Slots
+// themselves come from the configured schedule; a document only exists once
+// a slot has been booked or fenced.
+type DeliverySlotRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+type mongoDeliverySlot struct {
+	ID       string   `bson:"_id"`
+	OrderIDs []string `bson:"order_ids"`
+	Fence    int64    `bson:"fence,omitempty"` // Latest slot lock token written with
+}
+
+func NewDeliverySlotRepository(db *mongo.Database) *DeliverySlotRepository {
+	return &DeliverySlotRepository{
+		db:         db,
+		collection: db.Collection("delivery_slots"),
+	}
+}
+
+func (r *DeliverySlotRepository) GetReservationCounts(ctx context.Context, slotIDs []string) (map[string]int, error) {
+	defer observe("delivery_slot", "GetReservationCounts")()
+
+	cursor, err := r.collection.Find(ctx, bson.M{"_id": bson.M{"$in": slotIDs}})
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mSlots []mongoDeliverySlot
+	if err = cursor.All(ctx, &mSlots); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int, len(mSlots))
+	for _, mSlot := range mSlots {
+		counts[mSlot.ID] = len(mSlot.OrderIDs)
+	}
+
+	return counts, nil
+}
+
+func (r *DeliverySlotRepository) ReserveSlot(ctx context.Context, orderID string, slotID string, fence int64) error {
+	defer observe("delivery_slot", "ReserveSlot")()
+
+	return r.fencedUpdate(ctx, slotID, fence, bson.M{"$addToSet": bson.M{"order_ids": orderID}})
+}
+
+// Fence records fence as the slot's latest lock token without booking it,
+// for writes guarded by the slot lock that live in other collections.
+func (r *DeliverySlotRepository) Fence(ctx context.Context, slotID string, fence int64) error {
+	defer observe("delivery_slot", "Fence")()
+
+	return r.fencedUpdate(ctx, slotID, fence, bson.M{})
+}
+
+// fencedUpdate applies update unless the slot was written with a later
+// token. Such a slot doesn't match the filter, so the upsert then collides
+// with it on _id.
+func (r *DeliverySlotRepository) fencedUpdate(ctx context.Context, slotID string, fence int64, update bson.M) error {
+	update["$set"] = bson.M{"fence": fence}
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": slotID, "fence": bson.M{"$not": bson.M{"$gt": fence}}},
+		update,
+		options.Update().SetUpsert(true),
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		return domain.ErrStaleLock
+	}
+	return err
+}
+
+func (r *DeliverySlotRepository) ReleaseSlot(ctx context.Context, orderID string, slotID string) error {
+	defer observe("delivery_slot", "ReleaseSlot")()
+
+	_, err := r.collection.UpdateOne(ctx,
+		bson.M{"_id": slotID},
+		bson.M{"$pull": bson.M{"order_ids": orderID}},
+	)
+	return err
+}
@@ -0,0 +1,46 @@
+package mongodb
+
+// FieldCipher encrypts sensitive fields before they are written, so they
+// are unreadable in the database and its backups. Fields that are queried
+// by equality use the deterministic variant, and are looked up by all of
+// their DeterministicCandidates until every value is rotated.
+type FieldCipher interface {
+	Encrypt(plaintext string) (string, error)
+	EncryptDeterministic(plaintext string) (string, error)
+	DeterministicCandidates(plaintext string) ([]string, error)
+	Decrypt(ciphertext string) (string, error)
+	ActiveKeyID() string
+}
+
+// sealFields replaces each field with its ciphertext.
+func sealFields(encrypt func(string) (string, error), fields ...*string) error {
+	for _, field := range fields {
+		ciphertext, err := encrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = ciphertext
+	}
+	return nil
+}
+
+// openFields replaces each field with its plaintext.
+func openFields(cipher FieldCipher, fields ...*string) error {
+	for _, field := range fields {
+		plaintext, err := cipher.Decrypt(*field)
+		if err != nil {
+			return err
+		}
+		*field = plaintext
+	}
+	return nil
+}
+
+// keyIDMatch filters on a pii_key_id as it was read, so a rotation skips
+// documents rewritten since. Documents stored before encryption have none.
+func keyIDMatch(keyID string) interface{} {
+	if keyID == "" {
+		return nil
+	}
+	return keyID
+}
@@ -0,0 +1,15 @@
+package mongodb
+
+import (
+	"time"
+
+	"github.com/hsibAD/order-service/internal/metrics"
+)
+
+// observe times a repository call; use as defer observe("order", "Create")().
+func observe(repository, method string) func() {
+	start := time.Now()
+	return func() {
+		metrics.MongoOperationSeconds.WithLabelValues(repository, method).Observe(time.Since(start).Seconds())
+	}
+}
@@ -0,0 +1,128 @@
+package mongodb
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+type SlotHoldRepository struct {
+	db         *mongo.Database
+	collection *mongo.Collection
+}
+
+type mongoSlotHold struct {
+	Token     string    `bson:"_id"`
+	SlotID    string    `bson:"slot_id"`
+	UserID    string    `bson:"user_id"`
+	ExpiresAt time.Time `bson:"expires_at"`
+}
+
+func NewSlotHoldRepository(db *mongo.Database) *SlotHoldRepository {
+	return &SlotHoldRepository{
+		db:         db,
+		collection: db.Collection("delivery_slot_holds"),
+	}
+}
+
+func (r *SlotHoldRepository) Create(ctx context.Context, hold *domain.SlotHold) error {
+	defer observe("slot_hold", "Create")()
+
+	_, err := r.collection.InsertOne(ctx, toMongoSlotHold(hold))
+	return err
+}
+
+func (r *SlotHoldRepository) GetByToken(ctx context.Context, token string) (*domain.SlotHold, error) {
+	defer observe("slot_hold", "GetByToken")()
+
+	var mHold mongoSlotHold
+	err := r.collection.FindOne(ctx, bson.M{"_id": token}).Decode(&mHold)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.ErrInvalidHoldToken
+		}
+		return nil, err
+	}
+
+	return fromMongoSlotHold(&mHold), nil
+}
+
+func (r *SlotHoldRepository) Delete(ctx context.Context, token string) error {
+	defer observe("slot_hold", "Delete")()
+
+	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": token})
+	if err != nil {
+		return err
+	}
+
+	if result.DeletedCount == 0 {
+		return domain.ErrInvalidHoldToken
+	}
+
+	return nil
+}
+
+func (r *SlotHoldRepository) CountActive(ctx context.Context, slotIDs []string, now time.Time) (map[string]int, error) {
+	defer observe("slot_hold", "CountActive")()
+
+	filter := bson.M{
+		"slot_id":    bson.M{"$in": slotIDs},
+		"expires_at": bson.M{"$gt": now},
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+	defer cursor.Close(ctx)
+
+	var mHolds []mongoSlotHold
+	if err = cursor.All(ctx, &mHolds); err != nil {
+		return nil, err
+	}
+
+	counts := make(map[string]int)
+	for _, mHold := range mHolds {
+		counts[mHold.SlotID]++
+	}
+
+	return counts, nil
+}
+
+func (r *SlotHoldRepository) ClaimExpired(ctx context.Context, now time.Time) (*domain.SlotHold, error) {
+	defer observe("slot_hold", "ClaimExpired")()
+
+	// FindOneAndDelete hands each expired hold to exactly one replica
+	var mHold mongoSlotHold
+	err := r.collection.FindOneAndDelete(ctx, bson.M{"expires_at": bson.M{"$lte": now}}).Decode(&mHold)
+	if err != nil {
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return fromMongoSlotHold(&mHold), nil
+}
+
+func toMongoSlotHold(hold *domain.SlotHold) *mongoSlotHold {
+	return &mongoSlotHold{
+		Token:     hold.Token,
+		SlotID:    hold.SlotID,
+		UserID:    hold.UserID,
+		ExpiresAt: hold.ExpiresAt,
+	}
+}
+
+func fromMongoSlotHold(mHold *mongoSlotHold) *domain.SlotHold {
+	return &domain.SlotHold{
+		Token:     mHold.Token,
+		SlotID:    mHold.SlotID,
+		UserID:    mHold.UserID,
+		ExpiresAt: mHold.ExpiresAt,
+	}
+}
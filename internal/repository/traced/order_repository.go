@@ -0,0 +1,75 @@
+package traced
+
+import (
+	"context"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OrderRepository wraps another domain.OrderRepository with a span per call.
+// Placed outside the cache decorator, the span covers cache lookups and the
+// database calls they fall back to.
+type OrderRepository struct {
+	next domain.OrderRepository
+}
+
+func NewOrderRepository(next domain.OrderRepository) *OrderRepository {
+	return &OrderRepository{next: next}
+}
+
+func (r *OrderRepository) Create(ctx context.Context, order *domain.Order) (err error) {
+	ctx, span := start(ctx, "Create", attribute.String("order.user_id", order.UserID))
+	defer func() {
+		span.SetAttributes(attribute.String("order.id", order.ID))
+		tracing.End(span, err)
+	}()
+
+	return r.next.Create(ctx, order)
+}
+
+func (r *OrderRepository) GetByID(ctx context.Context, id string) (_ *domain.Order, err error) {
+	ctx, span := start(ctx, "GetByID", attribute.String("order.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return r.next.GetByID(ctx, id)
+}
+
+func (r *OrderRepository) GetByUserID(ctx context.Context, userID string, page, limit int) (_ []*domain.Order, _ int, err error) {
+	ctx, span := start(ctx, "GetByUserID",
+		attribute.String("order.user_id", userID),
+		attribute.Int("page", page),
+		attribute.Int("limit", limit))
+	defer func() { tracing.End(span, err) }()
+
+	return r.next.GetByUserID(ctx, userID, page, limit)
+}
+
+func (r *OrderRepository) Update(ctx context.Context, order *domain.Order) (err error) {
+	ctx, span := start(ctx, "Update", attribute.String("order.id", order.ID))
+	defer func() { tracing.End(span, err) }()
+
+	return r.next.Update(ctx, order)
+}
+
+func (r *OrderRepository) UpdateStatus(ctx context.Context, orderID string, status domain.OrderStatus) (err error) {
+	ctx, span := start(ctx, "UpdateStatus",
+		attribute.String("order.id", orderID),
+		attribute.String("order.status", string(status)))
+	defer func() { tracing.End(span, err) }()
+
+	return r.next.UpdateStatus(ctx, orderID, status)
+}
+
+func (r *OrderRepository) Delete(ctx context.Context, id string) (err error) {
+	ctx, span := start(ctx, "Delete", attribute.String("order.id", id))
+	defer func() { tracing.End(span, err) }()
+
+	return r.next.Delete(ctx, id)
+}
+
+func start(ctx context.Context, method string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return tracing.Start(ctx, "OrderRepository."+method, trace.WithAttributes(attrs...))
+}
@@ -1,36 +1,554 @@
-package server
-
-import (
-	"fmt"
-	"net"
-
-	"github.com/hsibAD/order-service/internal/config"
-	"github.com/hsibAD/order-service/internal/handler"
-	"google.golang.org/grpc"
-)
-
-type Server struct {
-	cfg    *config.Config
-	server *grpc.Server
-}
-
-func NewServer(cfg *config.Config) (*Server, error) {
-	server := grpc.NewServer()
-	
-	// Register services
-	handler.RegisterServices(server, cfg)
-
-	return &Server{
-		cfg:    cfg,
-		server: server,
-	}, nil
-}
-
-func (s *Server) Run() error {
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", s.cfg.Port))
-	if err != nil {
-		return fmt.Errorf("failed to listen: %v", err)
-	}
-
-	return s.server.Serve(lis)
-} 
\ No newline at end of file
+package server
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/auth"
+	"github.com/hsibAD/order-service/internal/config"
+	"github.com/hsibAD/order-service/internal/debug"
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/gateway"
+	"github.com/hsibAD/order-service/internal/handler"
+	"github.com/hsibAD/order-service/internal/health"
+	"github.com/hsibAD/order-service/internal/infrastructure/cache"
+	"github.com/hsibAD/order-service/internal/infrastructure/encryption"
+	"github.com/hsibAD/order-service/internal/infrastructure/events"
+	"github.com/hsibAD/order-service/internal/infrastructure/geocoding"
+	"github.com/hsibAD/order-service/internal/infrastructure/lock"
+	"github.com/hsibAD/order-service/internal/infrastructure/zones"
+	"github.com/hsibAD/order-service/internal/logging"
+	"github.com/hsibAD/order-service/internal/metrics"
+	"github.com/hsibAD/order-service/internal/ratelimit"
+	"github.com/hsibAD/order-service/internal/repository/cached"
+	"github.com/hsibAD/order-service/internal/repository/mongodb"
+	"github.com/hsibAD/order-service/internal/repository/traced"
+	"github.com/hsibAD/order-service/internal/tlsconfig"
+	"github.com/hsibAD/order-service/internal/usecase"
+	pb "github.com/hsibAD/order-service/proto"
+	"github.com/nats-io/nats.go"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/mongo/readpref"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	channelz "google.golang.org/grpc/channelz/service"
+	"google.golang.org/grpc/credentials"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+type Server struct {
+	cfg       *config.Config
+	current   atomic.Pointer[config.Config] // cfg with reloaded settings applied
+	logger    *zap.Logger
+	server    *grpc.Server
+	admin     *http.Server
+	gateway   *gateway.Gateway
+	health    *health.Monitor
+	tls       *tlsconfig.Server
+	limiter   *ratelimit.Limiter
+	mongo     *mongo.Client
+	cache     *cache.RedisCache
+	publisher *events.NATSPublisher
+	orders    *usecase.OrderService
+	slots     *usecase.SlotService
+	addresses *usecase.AddressService
+	rotators  []keyRotator
+}
+
+// keyRotator re-encrypts stored data under the active master key and
+// returns the number of records rewritten.
+type keyRotator func(ctx context.Context) (int, error)
+
+func NewServer(cfg *config.Config, logger *zap.Logger) (*Server, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	mongoTLS, err := clientTLS(cfg.Mongo.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("mongodb tls: %v", err)
+	}
+	redisTLS, err := clientTLS(cfg.Redis.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("redis tls: %v", err)
+	}
+	natsTLS, err := clientTLS(cfg.NATS.TLS)
+	if err != nil {
+		return nil, fmt.Errorf("nats tls: %v", err)
+	}
+
+	var serverTLS *tlsconfig.Server
+	if cfg.TLS.CertFile != "" {
+		serverTLS, err = tlsconfig.NewServer(tlsconfig.ServerOptions{
+			CertFile:           cfg.TLS.CertFile,
+			KeyFile:            cfg.TLS.KeyFile,
+			ClientCAFile:       cfg.TLS.ClientCAFile,
+			ClientCertOptional: cfg.TLS.ClientAuth == "optional",
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	mongoOptions := options.Client().ApplyURI(cfg.Mongo.URI)
+	if mongoTLS != nil {
+		mongoOptions.SetTLSConfig(mongoTLS)
+	}
+	mongoClient, err := mongo.Connect(ctx, mongoOptions)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to mongodb: %v", err)
+	}
+	db := mongoClient.Database(cfg.Mongo.Database)
+
+	fieldCipher, err := newKeyring(cfg)
+	if err != nil {
+		mongoClient.Disconnect(ctx)
+		return nil, err
+	}
+
+	redisCache := cache.NewRedisCache(cache.RedisConfig{
+		Addr:      cfg.Redis.Addr,
+		Password:  cfg.Redis.Password,
+		DB:        cfg.Redis.DB,
+		Namespace: cfg.Cache.Namespace,
+		L1Size:    cfg.Cache.L1Size,
+		L1TTL:     cfg.Cache.L1TTL,
+		TLSConfig: redisTLS,
+		Cipher:    fieldCipher,
+	})
+	metrics.Registry.MustRegister(redisCache.Collector())
+
+	profiles, err := events.NewPayloadProfiles(cfg.Events.PayloadProfile, cfg.Events.PayloadProfiles)
+	if err != nil {
+		mongoClient.Disconnect(ctx)
+		return nil, err
+	}
+
+	var natsOptions []nats.Option
+	if natsTLS != nil {
+		natsOptions = append(natsOptions, nats.Secure(natsTLS))
+	}
+	publisher, err := events.NewNATSPublisher(cfg.NATS.URL, profiles, natsOptions...)
+	if err != nil {
+		mongoClient.Disconnect(ctx)
+		return nil, fmt.Errorf("failed to connect to nats: %v", err)
+	}
+
+	zoneCatalog, err := zones.LoadFile(cfg.Addresses.ZonesFile)
+	if err != nil {
+		mongoClient.Disconnect(ctx)
+		publisher.Close()
+		return nil, err
+	}
+
+	mongoOrders := mongodb.NewOrderRepository(db, fieldCipher)
+	orderStore := traced.NewOrderRepository(mongoOrders)
+	orderRepo := traced.NewOrderRepository(cached.NewOrderRepository(
+		mongoOrders,
+		redisCache,
+		cfg.Cache.OrderTTL,
+		cfg.Cache.OrderNegativeTTL,
+	))
+	addressRepo := mongodb.NewDeliveryAddressRepository(db, fieldCipher)
+
+	geocoder, err := newGeocoder(cfg, redisCache)
+	if err != nil {
+		mongoClient.Disconnect(ctx)
+		publisher.Close()
+		return nil, err
+	}
+
+	locker := lock.NewLocker(redisCache.Client(), cfg.Cache.Namespace, cfg.Cache.LockTTL)
+
+	slots := usecase.NewSlotService(
+		usecase.SlotSchedule{
+			FirstHour:  cfg.Slots.FirstHour,
+			LastHour:   cfg.Slots.LastHour,
+			SlotLength: cfg.Slots.Length,
+			Capacity:   cfg.Slots.Capacity,
+			HoldTTL:    cfg.Slots.HoldTTL,
+			CacheTTL:   cfg.Slots.CacheTTL,
+		},
+		domain.DeliveryPricing{
+			BaseFee:                    cfg.Pricing.BaseFee,
+			SurgeUtilization:           cfg.Pricing.SurgeUtilization,
+			SurgeMultiplier:            cfg.Pricing.SurgeMultiplier,
+			MinimumOrderTotal:          cfg.Pricing.MinimumOrderTotal,
+			FreeDeliveryThreshold:      cfg.Pricing.FreeDeliveryThreshold,
+			ZoneFreeDeliveryThresholds: cfg.Pricing.ZoneFreeDeliveryThresholds,
+		},
+		mongodb.NewDeliverySlotRepository(db),
+		mongodb.NewSlotHoldRepository(db),
+		locker,
+		redisCache,
+		publisher,
+	)
+	orders := usecase.NewOrderService(orderRepo, orderStore, locker, slots, zoneCatalog, publisher, events.NewOrderFeed(publisher), cfg.Watch.HeartbeatInterval)
+	addresses := usecase.NewAddressService(addressRepo, zoneCatalog, geocoder, redisCache)
+	privacy := usecase.NewPrivacyService(orderRepo, orderStore, locker, addressRepo, redisCache, publisher, publisher)
+
+	logConfig := logging.Config{
+		SampleInitial:    cfg.Log.SampleInitial,
+		SampleThereafter: cfg.Log.SampleThereafter,
+	}
+	limiter := ratelimit.New(cfg.Auth.RateLimit, cfg.Auth.RateLimitBurst)
+	serviceRoles := make(map[string]auth.Role, len(cfg.TLS.ClientRoles))
+	for identity, role := range cfg.TLS.ClientRoles {
+		serviceRoles[identity] = auth.Role(role)
+	}
+	authenticator := auth.NewAuthenticator(cfg.Auth.JWTSecret, serviceRoles)
+	sampled := logging.Sampled(logger, logConfig)
+	serverOptions := []grpc.ServerOption{
+		// Incoming trace context is picked up before the interceptors run
+		grpc.StatsHandler(otelgrpc.NewServerHandler()),
+		grpc.ChainUnaryInterceptor(
+			// Outermost, so rejected tokens are logged too
+			logging.UnaryServerInterceptor(logger, sampled),
+			metrics.UnaryServerInterceptor(),
+			authenticator.UnaryServerInterceptor(),
+			limiter.UnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			logging.StreamServerInterceptor(logger, sampled),
+			metrics.StreamServerInterceptor(),
+			authenticator.StreamServerInterceptor(),
+			limiter.StreamServerInterceptor(),
+		),
+	}
+	var server *grpc.Server
+	if serverTLS != nil {
+		server = grpc.NewServer(append(serverOptions, grpc.Creds(credentials.NewTLS(serverTLS.Config())))...)
+	} else {
+		server = grpc.NewServer(serverOptions...)
+	}
+
+	monitor := health.NewMonitor(cfg.Health.Timeout, pb.OrderService_ServiceDesc.ServiceName)
+	monitor.Register("mongodb", health.CheckerFunc(func(ctx context.Context) error {
+		return mongoClient.Ping(ctx, readpref.Primary())
+	}))
+	monitor.Register("redis", redisCache)
+	monitor.Register("nats", publisher)
+
+	// Register services
+	orderHandler := handler.NewOrderHandler(orders, addresses, slots, privacy)
+	handler.RegisterServices(server, orderHandler)
+	healthpb.RegisterHealthServer(server, monitor.Server())
+
+	var restGateway *gateway.Gateway
+	if cfg.HTTPPort != "" {
+		// The gateway's own in-process server, behind the same interceptors
+		gatewayServer := grpc.NewServer(append([]grpc.ServerOption{
+			grpc.ChainUnaryInterceptor(gateway.UnaryServerInterceptor()),
+			grpc.ChainStreamInterceptor(gateway.StreamServerInterceptor()),
+		}, serverOptions...)...)
+		handler.RegisterServices(gatewayServer, orderHandler)
+
+		var gatewayTLS *tls.Config
+		if serverTLS != nil {
+			gatewayTLS = serverTLS.Config()
+		}
+		restGateway, err = gateway.New(context.Background(), net.JoinHostPort(cfg.Host, cfg.HTTPPort), gatewayTLS, gatewayServer)
+		if err != nil {
+			mongoClient.Disconnect(ctx)
+			publisher.Close()
+			return nil, err
+		}
+	}
+
+	adminMux := http.NewServeMux()
+	adminMux.Handle("/metrics", metrics.Handler())
+	adminMux.Handle("/healthz", monitor.LivenessHandler())
+	adminMux.Handle("/readyz", monitor.ReadinessHandler())
+
+	s := &Server{
+		cfg:       cfg,
+		logger:    logger,
+		server:    server,
+		admin:     &http.Server{Addr: net.JoinHostPort(cfg.AdminHost, cfg.AdminPort), Handler: adminMux, ReadHeaderTimeout: 5 * time.Second},
+		gateway:   restGateway,
+		health:    monitor,
+		tls:       serverTLS,
+		limiter:   limiter,
+		mongo:     mongoClient,
+		cache:     redisCache,
+		publisher: publisher,
+		orders:    orders,
+		slots:     slots,
+		addresses: addresses,
+		rotators: []keyRotator{
+			// Cached copies are encrypted under the key they were read with
+			func(ctx context.Context) (int, error) {
+				return mongoOrders.RotateEncryptionKeys(ctx, redisCache.DeleteOrder)
+			},
+			func(ctx context.Context) (int, error) {
+				return addressRepo.RotateEncryptionKeys(ctx, redisCache.DeleteDeliveryAddresses)
+			},
+		},
+	}
+	s.current.Store(cfg)
+
+	if cfg.DevMode {
+		logger.Warn("development mode enabled, serving reflection, channelz and /debug/")
+		reflection.Register(server)
+		channelz.RegisterChannelzServiceToServer(server)
+		adminMux.Handle("/debug/", debug.Handler(server, s.current.Load, monitor))
+	}
+
+	return s, nil
+}
+
+// clientTLS builds the TLS configuration for a dependency, or nil when TLS
+// is disabled for it.
+func clientTLS(c config.ClientTLSConfig) (*tls.Config, error) {
+	if !c.Enabled {
+		return nil, nil
+	}
+	return tlsconfig.Client(tlsconfig.ClientOptions{
+		CAFile:     c.CAFile,
+		CertFile:   c.CertFile,
+		KeyFile:    c.KeyFile,
+		ServerName: c.ServerName,
+	})
+}
+
+// newGeocoder builds the configured provider behind the Redis cache. It
+// returns nil when geocoding is disabled.
+func newGeocoder(cfg *config.Config, redisCache *cache.RedisCache) (domain.Geocoder, error) {
+	var provider domain.Geocoder
+	switch cfg.Geocoder.Provider {
+	case "":
+		return nil, nil
+	case "static":
+		static, err := geocoding.LoadFile(cfg.Geocoder.File)
+		if err != nil {
+			return nil, err
+		}
+		provider = static
+	case "http":
+		provider = geocoding.NewHTTPGeocoder(cfg.Geocoder.URL, cfg.Geocoder.APIKey, cfg.Geocoder.Timeout)
+	default:
+		return nil, fmt.Errorf("unknown geocoder %q", cfg.Geocoder.Provider)
+	}
+
+	return geocoding.NewCachedGeocoder(provider, redisCache, cfg.Geocoder.CacheTTL), nil
+}
+
+// newKeyring loads the field encryption master keys. Values written with the
+// single-key cipher used before stay readable through the legacy cipher.
+func newKeyring(cfg *config.Config) (*encryption.Keyring, error) {
+	var legacy *encryption.Cipher
+	if cfg.Encryption.Key != "" {
+		var err error
+		if legacy, err = encryption.NewCipherFromSecret(cfg.Encryption.Key); err != nil {
+			return nil, err
+		}
+	}
+
+	var (
+		keys map[string][]byte
+		err  error
+	)
+	active := cfg.Encryption.ActiveKey
+	switch {
+	case cfg.Encryption.KeysFile != "":
+		var fileActive string
+		keys, fileActive, err = encryption.LoadKeyFile(cfg.Encryption.KeysFile)
+		if active == "" {
+			active = fileActive
+		}
+	case cfg.Encryption.Keys != "":
+		keys, err = encryption.ParseKeys(cfg.Encryption.Keys)
+	default:
+		keys = map[string][]byte{"default": encryption.KeyFromSecret(cfg.Encryption.Key)}
+		active = "default"
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return encryption.NewKeyring(keys, active, legacy)
+}
+
+// Reload applies the reloadable settings of cfg, see config.Reloader.
+func (s *Server) Reload(cfg *config.Config) {
+	s.current.Store(cfg)
+	s.limiter.SetLimits(cfg.Auth.RateLimit, cfg.Auth.RateLimitBurst)
+}
+
+// runKeyRotation moves stored data onto the active master key every interval
+// until ctx is done, so retired keys can eventually be removed.
+func (s *Server) runKeyRotation(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		for _, rotator := range s.rotators {
+			rotated, err := rotator(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("encryption key rotation failed", zap.Error(err))
+			}
+			if rotated > 0 {
+				logging.FromContext(ctx).Info("re-encrypted records under the active key", zap.Int("count", rotated))
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Run serves until ctx is done, then shuts down gracefully: readiness goes
+// NOT_SERVING, after Shutdown.DrainDelay in-flight calls get up to
+// Shutdown.Timeout to finish, and the background jobs and connections are
+// stopped in order.
+func (s *Server) Run(ctx context.Context) error {
+	lis, err := net.Listen("tcp", net.JoinHostPort(s.cfg.Host, s.cfg.Port))
+	if err != nil {
+		return fmt.Errorf("failed to listen: %v", err)
+	}
+
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	defer stopJobs()
+	var jobs sync.WaitGroup
+	startJob := func(name string, run func(ctx context.Context)) {
+		jobs.Add(1)
+		go func() {
+			defer jobs.Done()
+			run(logging.NewContext(jobsCtx, s.logger.Named(name)))
+		}()
+	}
+
+	startJob("cache-invalidation", s.cache.ListenForInvalidations)
+	startJob("health", func(ctx context.Context) { s.health.Run(ctx, s.cfg.Health.Interval) })
+	startJob("hold-sweeper", func(ctx context.Context) { s.slots.RunHoldSweeper(ctx, s.cfg.Slots.SweepInterval) })
+	startJob("address-merger", func(ctx context.Context) { s.addresses.RunDuplicateMerger(ctx, s.cfg.Addresses.MergeInterval) })
+	startJob("key-rotation", func(ctx context.Context) { s.runKeyRotation(ctx, s.cfg.Encryption.RotationInterval) })
+	startJob("slot-metrics", func(ctx context.Context) { s.slots.RunUtilizationReporter(ctx, s.cfg.Slots.MetricsInterval) })
+	if s.tls != nil {
+		startJob("tls-reload", func(ctx context.Context) { s.tls.Run(ctx, s.cfg.TLS.ReloadInterval) })
+	}
+
+	errs := make(chan error, 3)
+	go func() {
+		s.logger.Info("admin server listening", zap.String("addr", s.admin.Addr))
+		if err := s.admin.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			errs <- fmt.Errorf("admin server failed: %v", err)
+		}
+	}()
+	go func() {
+		s.logger.Info("grpc server listening", zap.String("addr", lis.Addr().String()), zap.Bool("tls", s.tls != nil))
+		if err := s.server.Serve(lis); err != nil {
+			errs <- fmt.Errorf("grpc server failed: %v", err)
+		}
+	}()
+	if s.gateway != nil {
+		go func() {
+			s.logger.Info("rest gateway listening", zap.String("addr", s.gateway.Addr()))
+			if err := s.gateway.Serve(); err != nil {
+				errs <- fmt.Errorf("rest gateway failed: %v", err)
+			}
+		}()
+	}
+
+	var serveErr error
+	select {
+	case <-ctx.Done():
+		s.logger.Info("shutting down")
+	case serveErr = <-errs:
+		s.logger.Error("shutting down after server failure", zap.Error(serveErr))
+	}
+
+	s.shutdown(stopJobs, &jobs)
+	return serveErr
+}
+
+func (s *Server) shutdown(stopJobs context.CancelFunc, jobs *sync.WaitGroup) {
+	// Give load balancers time to see NOT_SERVING before the listener closes
+	s.health.Shutdown()
+	if s.cfg.Shutdown.DrainDelay > 0 {
+		s.logger.Info("waiting for load balancers to drain", zap.Duration("delay", s.cfg.Shutdown.DrainDelay))
+		time.Sleep(s.cfg.Shutdown.DrainDelay)
+	}
+
+	// The timeout covers the shutdown proper, not the drain delay
+	ctx, cancel := context.WithTimeout(context.Background(), s.cfg.Shutdown.Timeout)
+	defer cancel()
+
+	// Watch streams never finish on their own; their clients resume on
+	// another replica
+	s.orders.StopWatches()
+
+	var servers sync.WaitGroup
+	if s.gateway != nil {
+		servers.Add(1)
+		go func() {
+			defer servers.Done()
+			if err := s.gateway.Shutdown(ctx); err != nil {
+				s.logger.Warn("failed to stop rest gateway", zap.Error(err))
+			}
+		}()
+	}
+
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-ctx.Done():
+		s.logger.Warn("shutdown deadline passed, cancelling in-flight calls")
+		s.server.Stop()
+	}
+	servers.Wait()
+
+	if err := s.admin.Shutdown(ctx); err != nil {
+		s.logger.Warn("failed to stop admin server", zap.Error(err))
+	}
+
+	stopJobs()
+	if !waitGroupDone(ctx, jobs) {
+		s.logger.Warn("background jobs still running at shutdown deadline")
+	}
+
+	// Publishes still buffered are flushed before the connection closes
+	if err := s.publisher.Drain(ctx); err != nil {
+		s.logger.Warn("failed to drain nats connection", zap.Error(err))
+	}
+	if err := s.cache.Close(); err != nil {
+		s.logger.Warn("failed to close redis connection", zap.Error(err))
+	}
+	if err := s.mongo.Disconnect(ctx); err != nil {
+		s.logger.Warn("failed to disconnect from mongodb", zap.Error(err))
+	}
+
+	s.logger.Info("shutdown complete")
+}
+
+// waitGroupDone waits for wg until ctx is done and reports whether it
+// finished.
+func waitGroupDone(ctx context.Context, wg *sync.WaitGroup) bool {
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
@@ -0,0 +1,41 @@
+package tlsconfig
+
+import (
+	"crypto/tls"
+	"fmt"
+)
+
+// ClientOptions configures TLS to a dependency. CAFile replaces the system
+// roots; CertFile and KeyFile present a client certificate for mutual TLS.
+type ClientOptions struct {
+	CAFile     string
+	CertFile   string
+	KeyFile    string
+	ServerName string // Overrides the name verified against the certificate
+}
+
+// Client builds the TLS configuration for an outbound connection.
+func Client(opts ClientOptions) (*tls.Config, error) {
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: opts.ServerName,
+	}
+
+	if opts.CAFile != "" {
+		pool, err := loadCertPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		cfg.RootCAs = pool
+	}
+
+	if opts.CertFile != "" || opts.KeyFile != "" {
+		cert, err := tls.LoadX509KeyPair(opts.CertFile, opts.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load TLS client certificate: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,169 @@
+package tlsconfig
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+)
+
+// ServerOptions locates the server certificate and, for mutual TLS, the CA
+// bundle client certificates are verified against.
+type ServerOptions struct {
+	CertFile     string
+	KeyFile      string
+	ClientCAFile string
+
+	// Accept clients without a certificate; those that present one are still
+	// verified. Only used with ClientCAFile.
+	ClientCertOptional bool
+}
+
+// Server serves a certificate and client CA pool that follow changes to
+// their files, so renewed certificates are picked up without a restart.
+type Server struct {
+	opts ServerOptions
+
+	mu       sync.RWMutex
+	cert     *tls.Certificate
+	clientCA *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+// NewServer loads the files in opts. It fails if any of them is unreadable.
+func NewServer(opts ServerOptions) (*Server, error) {
+	s := &Server{opts: opts}
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Config returns the TLS configuration for a listener. Each handshake uses
+// the latest certificate and client CA pool.
+func (s *Server) Config() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			s.mu.RLock()
+			defer s.mu.RUnlock()
+
+			cfg := &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*s.cert},
+				// HTTP/1.1 for REST clients of the gateway
+				NextProtos: []string{"h2", "http/1.1"},
+			}
+			if s.clientCA != nil {
+				cfg.ClientCAs = s.clientCA
+				cfg.ClientAuth = tls.RequireAndVerifyClientCert
+				if s.opts.ClientCertOptional {
+					cfg.ClientAuth = tls.VerifyClientCertIfGiven
+				}
+			}
+			return cfg, nil
+		},
+	}
+}
+
+// Run reloads the files every interval when one of them changed, until ctx
+// is done. A failed reload keeps the previous certificate in use.
+func (s *Server) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		changed, err := s.changed()
+		if err != nil {
+			logging.FromContext(ctx).Warn("failed to check TLS files", zap.Error(err))
+			continue
+		}
+		if !changed {
+			continue
+		}
+		if err := s.load(); err != nil {
+			logging.FromContext(ctx).Error("failed to reload TLS certificate", zap.Error(err))
+			continue
+		}
+		logging.FromContext(ctx).Info("reloaded TLS certificate", zap.String("cert_file", s.opts.CertFile))
+	}
+}
+
+func (s *Server) files() []string {
+	files := []string{s.opts.CertFile, s.opts.KeyFile}
+	if s.opts.ClientCAFile != "" {
+		files = append(files, s.opts.ClientCAFile)
+	}
+	return files
+}
+
+func (s *Server) changed() (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, file := range s.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return false, err
+		}
+		if !info.ModTime().Equal(s.modTimes[file]) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *Server) load() error {
+	// Stat before reading, so a write racing the load is seen next time
+	modTimes := make(map[string]time.Time)
+	for _, file := range s.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS file: %v", err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(s.opts.CertFile, s.opts.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+
+	var clientCA *x509.CertPool
+	if s.opts.ClientCAFile != "" {
+		if clientCA, err = loadCertPool(s.opts.ClientCAFile); err != nil {
+			return err
+		}
+	}
+
+	s.mu.Lock()
+	s.cert = &cert
+	s.clientCA = clientCA
+	s.modTimes = modTimes
+	s.mu.Unlock()
+	return nil
+}
+
+func loadCertPool(file string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file: %v", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no certificates found in CA file %s", file)
+	}
+	return pool, nil
+}
@@ -0,0 +1,217 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"go.uber.org/zap"
+)
+
+type AddressCache interface {
+	DeleteDeliveryAddresses(ctx context.Context, userID string) error
+}
+
+type AddressService struct {
+	addresses domain.DeliveryAddressRepository
+	zones     *domain.ZoneCatalog
+	geocoder  domain.Geocoder // Optional
+	cache     AddressCache
+}
+
+func NewAddressService(addresses domain.DeliveryAddressRepository, zones *domain.ZoneCatalog, geocoder domain.Geocoder, cache AddressCache) *AddressService {
+	return &AddressService{
+		addresses: addresses,
+		zones:     zones,
+		geocoder:  geocoder,
+		cache:     cache,
+	}
+}
+
+// AddAddress geocodes and saves a validated address after making sure we
+// deliver there. When the user already saved the same address, that one is
+// returned instead of a copy, filled in with any extra detail, and the bool
+// result is true.
+func (s *AddressService) AddAddress(ctx context.Context, address *domain.DeliveryAddress) (*domain.DeliveryAddress, bool, error) {
+	existing, err := s.findDuplicate(ctx, address)
+	if err != nil {
+		return nil, false, err
+	}
+	if existing != nil {
+		existing, err = s.reuse(ctx, existing, address)
+		return existing, true, err
+	}
+
+	location, err := s.geocode(ctx, address)
+	if err != nil {
+		return nil, false, err
+	}
+	address.Location = location
+
+	if err := s.zones.Ensure(address, address.Location); err != nil {
+		return nil, false, err
+	}
+
+	if err := s.addresses.Create(ctx, address); err != nil {
+		return nil, false, err
+	}
+
+	if address.IsDefault {
+		if err := s.addresses.SetDefault(ctx, address.UserID, address.ID); err != nil {
+			return nil, false, err
+		}
+	}
+
+	ctx = logging.With(ctx, logging.Address(address))
+
+	// The cached list is rebuilt on the next read
+	_ = s.cache.DeleteDeliveryAddresses(ctx, address.UserID)
+
+	return address, false, nil
+}
+
+// findDuplicate only looks at addresses of the same building and phone,
+// which IsDuplicateOf requires anyway.
+func (s *AddressService) findDuplicate(ctx context.Context, address *domain.DeliveryAddress) (*domain.DeliveryAddress, error) {
+	saved, err := s.addresses.GetByFingerprint(ctx, address.UserID, address.Fingerprint(), address.Phone)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, existing := range saved {
+		if address.IsDuplicateOf(existing) {
+			return existing, nil
+		}
+	}
+	return nil, nil
+}
+
+func (s *AddressService) reuse(ctx context.Context, existing *domain.DeliveryAddress, address *domain.DeliveryAddress) (*domain.DeliveryAddress, error) {
+	wasDefault := existing.IsDefault
+	if !existing.MergeDuplicate(address) {
+		return existing, nil
+	}
+
+	if err := s.addresses.Update(ctx, existing); err != nil {
+		return nil, err
+	}
+	if existing.IsDefault && !wasDefault {
+		if err := s.addresses.SetDefault(ctx, existing.UserID, existing.ID); err != nil {
+			return nil, err
+		}
+	}
+
+	_ = s.cache.DeleteDeliveryAddresses(ctx, existing.UserID)
+	return existing, nil
+}
+
+// MergeDuplicates collapses each user's duplicate addresses into the oldest
+// one, which keeps the default flag if any duplicate had it. It returns the
+// number of addresses removed.
+func (s *AddressService) MergeDuplicates(ctx context.Context) (int, error) {
+	userIDs, err := s.addresses.ListUserIDsWithMultipleAddresses(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	merged := 0
+	for _, userID := range userIDs {
+		n, err := s.mergeUserDuplicates(ctx, userID)
+		merged += n
+		if err != nil {
+			return merged, fmt.Errorf("failed to merge addresses of user %s: %v", userID, err)
+		}
+	}
+	return merged, nil
+}
+
+func (s *AddressService) mergeUserDuplicates(ctx context.Context, userID string) (int, error) {
+	// Oldest first, as the repository returns them in insertion order
+	saved, err := s.addresses.GetByUserID(ctx, userID)
+	if err != nil {
+		return 0, err
+	}
+
+	merged := 0
+	kept := make([]*domain.DeliveryAddress, 0, len(saved))
+	for _, address := range saved {
+		var keeper *domain.DeliveryAddress
+		for _, candidate := range kept {
+			if address.IsDuplicateOf(candidate) {
+				keeper = candidate
+				break
+			}
+		}
+		if keeper == nil {
+			kept = append(kept, address)
+			continue
+		}
+
+		wasDefault := keeper.IsDefault
+		if keeper.MergeDuplicate(address) {
+			if err := s.addresses.Update(ctx, keeper); err != nil {
+				return merged, err
+			}
+		}
+
+		// Another replica may have merged it already
+		if err := s.addresses.Delete(ctx, address.ID); err != nil && !errors.Is(err, domain.ErrInvalidAddressID) {
+			return merged, err
+		}
+		merged++
+
+		if keeper.IsDefault && !wasDefault {
+			if err := s.addresses.SetDefault(ctx, userID, keeper.ID); err != nil {
+				return merged, err
+			}
+		}
+	}
+
+	if merged > 0 {
+		_ = s.cache.DeleteDeliveryAddresses(ctx, userID)
+	}
+	return merged, nil
+}
+
+// RunDuplicateMerger merges duplicate addresses every interval until ctx is
+// done.
+func (s *AddressService) RunDuplicateMerger(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			merged, err := s.MergeDuplicates(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to merge duplicate addresses", zap.Error(err))
+			}
+			if merged > 0 {
+				logging.FromContext(ctx).Info("merged duplicate addresses", zap.Int("count", merged))
+			}
+		}
+	}
+}
+
+func (s *AddressService) CheckServiceability(postalCode string, country string, location *domain.GeoPoint) domain.Serviceability {
+	return s.zones.Check(postalCode, country, location)
+}
+
+// geocode looks up the address coordinates. Addresses the provider can't
+// place are still accepted and routed by postal code.
+func (s *AddressService) geocode(ctx context.Context, address *domain.DeliveryAddress) (*domain.GeoPoint, error) {
+	if s.geocoder == nil {
+		return nil, nil
+	}
+
+	location, err := s.geocoder.Geocode(ctx, address)
+	if errors.Is(err, domain.ErrLocationNotFound) {
+		return nil, nil
+	}
+	return location, err
+}
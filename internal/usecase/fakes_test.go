@@ -0,0 +1,59 @@
+package usecase
+
+import (
+	"context"
+	"sync"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// publishedEvent records one call to fakePublisher.
+type publishedEvent struct {
+	Kind     string
+	OrderID  string
+	UserID   string
+	OrderIDs []string
+	Hold     *domain.SlotHold
+}
+
+type fakePublisher struct {
+	mu     sync.Mutex
+	events []publishedEvent
+}
+
+func (p *fakePublisher) record(event publishedEvent) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+func (p *fakePublisher) published() []publishedEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]publishedEvent(nil), p.events...)
+}
+
+func (p *fakePublisher) PublishOrderCreated(ctx context.Context, order *domain.Order) error {
+	return p.record(publishedEvent{Kind: "OrderCreated", OrderID: order.ID})
+}
+
+func (p *fakePublisher) PublishOrderStatusUpdated(ctx context.Context, order *domain.Order) error {
+	return p.record(publishedEvent{Kind: "OrderStatusUpdated", OrderID: order.ID})
+}
+
+func (p *fakePublisher) PublishOrderCancelled(ctx context.Context, order *domain.Order) error {
+	return p.record(publishedEvent{Kind: "OrderCancelled", OrderID: order.ID})
+}
+
+func (p *fakePublisher) PublishCourierAssigned(ctx context.Context, order *domain.Order) error {
+	return p.record(publishedEvent{Kind: "CourierAssigned", OrderID: order.ID})
+}
+
+func (p *fakePublisher) PublishUserDataErased(ctx context.Context, userID string, orderIDs []string) error {
+	return p.record(publishedEvent{Kind: "UserDataErased", UserID: userID, OrderIDs: orderIDs})
+}
+
+func (p *fakePublisher) PublishDeliverySlotHoldExpired(ctx context.Context, hold *domain.SlotHold) error {
+	return p.record(publishedEvent{Kind: "DeliverySlotHoldExpired", Hold: hold})
+}
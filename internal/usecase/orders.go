@@ -0,0 +1,249 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"github.com/hsibAD/order-service/internal/metrics"
+	"go.uber.org/zap"
+)
+
+type CreateOrderInput struct {
+	UserID          string
+	Items           []domain.OrderItem
+	DeliveryAddress *domain.DeliveryAddress
+	DeliveryTime    time.Time // Optional when HoldToken is set
+	HoldToken       string
+}
+
+// Event names of OrderUpdates that don't come from an order event.
+const (
+	OrderUpdateSnapshot  = "Snapshot"
+	OrderUpdateHeartbeat = "Heartbeat"
+)
+
+// ErrWatchStopped ends the order watches of a replica that shuts down.
+var ErrWatchStopped = errors.New("order watch stopped, resume from the last version")
+
+// OrderUpdate is one message of an order watch.
+type OrderUpdate struct {
+	Order   *domain.Order // Nil for heartbeats
+	Version uint64
+	Event   string
+}
+
+// OrderLocker serializes changes to a single order across replicas, so a
+// read-modify-write of one replica doesn't undo another's.
+type OrderLocker interface {
+	WithOrderLock(ctx context.Context, orderID string, fn func(ctx context.Context, token int64) error) error
+}
+
+type OrderService struct {
+	orders    domain.OrderRepository
+	store     domain.OrderRepository // Uncached, read under the order lock
+	locker    OrderLocker
+	slots     *SlotService
+	zones     *domain.ZoneCatalog
+	publisher domain.EventPublisher
+	changes   domain.OrderChangeFeed
+	heartbeat time.Duration // Interval of watch heartbeats
+
+	// watches is cancelled by StopWatches
+	watches     context.Context
+	stopWatches context.CancelFunc
+}
+
+// NewOrderService builds the service. store is the repository behind the
+// orders cache; read-modify-writes under the order lock read from it, as a
+// cached copy may predate the previous lock holder's write.
+func NewOrderService(orders domain.OrderRepository, store domain.OrderRepository, locker OrderLocker, slots *SlotService, zones *domain.ZoneCatalog, publisher domain.EventPublisher, changes domain.OrderChangeFeed, heartbeat time.Duration) *OrderService {
+	watches, stopWatches := context.WithCancel(context.Background())
+	return &OrderService{
+		orders:      orders,
+		store:       store,
+		locker:      locker,
+		slots:       slots,
+		zones:       zones,
+		publisher:   publisher,
+		changes:     changes,
+		heartbeat:   heartbeat,
+		watches:     watches,
+		stopWatches: stopWatches,
+	}
+}
+
+// CreateOrder stores a new order and books its delivery slot, consuming the
+// checkout hold when one is given.
+func (s *OrderService) CreateOrder(ctx context.Context, input CreateOrderInput) (*domain.Order, error) {
+	deliveryTime := input.DeliveryTime
+	if input.HoldToken != "" {
+		hold, err := s.slots.GetHold(ctx, input.UserID, input.HoldToken)
+		if err != nil {
+			return nil, err
+		}
+
+		slot, err := s.slots.parseSlotID(hold.SlotID)
+		if err != nil {
+			return nil, err
+		}
+
+		if deliveryTime.IsZero() {
+			deliveryTime = slot.StartTime
+		} else if !deliveryTime.Equal(slot.StartTime) {
+			return nil, domain.ErrHoldSlotMismatch
+		}
+	}
+
+	// Every bad field is reported at once, before the delivery is priced
+	if err := domain.ValidateOrderInput(input.UserID, input.Items, deliveryTime); err != nil {
+		return nil, err
+	}
+
+	if input.DeliveryAddress != nil {
+		if err := s.zones.Ensure(input.DeliveryAddress, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	// Also rejects times that aren't a slot start before anything is stored
+	deliveryFee, err := s.slots.QuoteDelivery(ctx, deliveryTime, postalCode(input.DeliveryAddress), domain.ItemsTotal(input.Items))
+	if err != nil {
+		return nil, err
+	}
+
+	order, err := domain.NewOrder(input.UserID, input.Items, input.DeliveryAddress, deliveryTime, deliveryFee)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.orders.Create(ctx, order); err != nil {
+		return nil, err
+	}
+
+	if err := s.slots.ReserveSlot(ctx, order.ID, order.UserID, order.DeliveryTime, input.HoldToken); err != nil {
+		// Without a slot the order can't be delivered, so roll it back
+		if err := s.orders.Delete(ctx, order.ID); err != nil {
+			logging.FromContext(ctx).Error("failed to roll back order without slot",
+				logging.Order(order), zap.Error(err))
+		}
+		return nil, err
+	}
+
+	metrics.OrdersCreated.WithLabelValues(string(order.Status), order.Currency).Inc()
+	ctx = logging.With(ctx, logging.Order(order))
+
+	// The order is already stored; a lost event must not fail the request
+	_ = s.publisher.PublishOrderCreated(ctx, order)
+
+	return order, nil
+}
+
+func (s *OrderService) GetOrder(ctx context.Context, orderID string) (*domain.Order, error) {
+	return s.orders.GetByID(ctx, orderID)
+}
+
+// AssignCourier hands the order to courierID, who from then on sees the
+// access code of its delivery address.
+func (s *OrderService) AssignCourier(ctx context.Context, orderID, courierID string) (*domain.Order, error) {
+	var order *domain.Order
+	err := s.locker.WithOrderLock(ctx, orderID, func(ctx context.Context, _ int64) error {
+		var err error
+		if order, err = s.store.GetByID(ctx, orderID); err != nil {
+			return err
+		}
+		if err := order.AssignCourier(courierID); err != nil {
+			return err
+		}
+		return s.orders.Update(ctx, order)
+	})
+	if err != nil {
+		return nil, err
+	}
+	ctx = logging.With(ctx, logging.Order(order))
+
+	// The assignment is already stored; a lost event must not fail the request
+	_ = s.publisher.PublishCourierAssigned(ctx, order)
+
+	return order, nil
+}
+
+// WatchOrder passes the order to send every time it changes, starting with
+// its current state unless since is a version whose later changes can still
+// be replayed. Between changes a heartbeat is sent every heartbeat interval.
+// It returns nil once the order is delivered or cancelled, and
+// ErrWatchStopped when StopWatches is called.
+func (s *OrderService) WatchOrder(ctx context.Context, orderID string, since uint64, send func(OrderUpdate) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer context.AfterFunc(s.watches, cancel)()
+
+	// Subscribe before reading the order, so no change falls in between
+	version, changes, err := s.changes.Watch(ctx, orderID, since)
+	if err != nil {
+		return s.watchErr(err)
+	}
+
+	order, err := s.orders.GetByID(ctx, orderID)
+	if err != nil {
+		return s.watchErr(err)
+	}
+	if since == 0 || version != since {
+		if err := send(OrderUpdate{Order: order, Version: version, Event: OrderUpdateSnapshot}); err != nil {
+			return err
+		}
+	}
+	if order.IsFinal() {
+		return nil
+	}
+
+	ticker := time.NewTicker(s.heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return s.watchErr(ctx.Err())
+
+		case change := <-changes:
+			order, err := s.orders.GetByID(ctx, orderID)
+			if err != nil {
+				return s.watchErr(err)
+			}
+			version = change.Version
+			if err := send(OrderUpdate{Order: order, Version: version, Event: change.Event}); err != nil {
+				return err
+			}
+			if order.IsFinal() {
+				return nil
+			}
+			ticker.Reset(s.heartbeat)
+
+		case <-ticker.C:
+			if err := send(OrderUpdate{Version: version, Event: OrderUpdateHeartbeat}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// StopWatches ends all running and future order watches with
+// ErrWatchStopped, so clients reconnect to another replica.
+func (s *OrderService) StopWatches() {
+	s.stopWatches()
+}
+
+func (s *OrderService) watchErr(err error) error {
+	if s.watches.Err() != nil {
+		return ErrWatchStopped
+	}
+	return err
+}
+
+func postalCode(address *domain.DeliveryAddress) string {
+	if address == nil {
+		return ""
+	}
+	return address.PostalCode
+}
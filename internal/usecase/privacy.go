@@ -0,0 +1,174 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// Page size used when walking a user's order history
+const privacyBatchSize = 100
+
+// UserDataCache is the subset of the cache that holds copies of user data.
+type UserDataCache interface {
+	DeleteOrder(ctx context.Context, orderID string) error
+	DeleteDeliveryAddresses(ctx context.Context, userID string) error
+}
+
+// EventLog is the stream of published events, whose order events can carry
+// the delivery address.
+type EventLog interface {
+	PurgeOrderEvents(ctx context.Context, orderID string) error
+}
+
+// UserDataExport is everything the service stores about a single user.
+type UserDataExport struct {
+	UserID     string
+	Orders     []*domain.Order
+	Addresses  []*domain.DeliveryAddress
+	ExportedAt time.Time
+}
+
+// ErasureResult summarises what an erasure request touched.
+type ErasureResult struct {
+	OrdersPseudonymized int
+	AddressesDeleted    int
+}
+
+// PrivacyService answers subject access and erasure requests.
+type PrivacyService struct {
+	orders    domain.OrderRepository
+	store     domain.OrderRepository // Uncached, read under the order lock
+	locker    OrderLocker
+	addresses domain.DeliveryAddressRepository
+	cache     UserDataCache
+	publisher domain.EventPublisher
+	events    EventLog
+}
+
+func NewPrivacyService(
+	orders domain.OrderRepository,
+	store domain.OrderRepository,
+	locker OrderLocker,
+	addresses domain.DeliveryAddressRepository,
+	cache UserDataCache,
+	publisher domain.EventPublisher,
+	events EventLog,
+) *PrivacyService {
+	return &PrivacyService{
+		orders:    orders,
+		store:     store,
+		locker:    locker,
+		addresses: addresses,
+		cache:     cache,
+		publisher: publisher,
+		events:    events,
+	}
+}
+
+// ExportUserData collects all orders and saved delivery addresses of a user.
+func (s *PrivacyService) ExportUserData(ctx context.Context, userID string) (*UserDataExport, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	orders, err := s.allOrders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.addresses.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load addresses: %v", err)
+	}
+
+	return &UserDataExport{
+		UserID:     userID,
+		Orders:     orders,
+		Addresses:  addresses,
+		ExportedAt: time.Now(),
+	}, nil
+}
+
+// EraseUserData pseudonymizes the address snapshots on the user's orders,
+// deletes their saved addresses and drops any cached copies and published
+// events of the orders. Prices and items are kept so financial records stay
+// intact.
+func (s *PrivacyService) EraseUserData(ctx context.Context, userID string) (*ErasureResult, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	orders, err := s.allOrders(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ErasureResult{}
+	orderIDs := make([]string, 0, len(orders))
+	for _, order := range orders {
+		if err := s.pseudonymize(ctx, order.ID); err != nil {
+			return nil, fmt.Errorf("failed to pseudonymize order %s: %v", order.ID, err)
+		}
+		if err := s.cache.DeleteOrder(ctx, order.ID); err != nil {
+			return nil, fmt.Errorf("failed to purge cached order %s: %v", order.ID, err)
+		}
+		if err := s.events.PurgeOrderEvents(ctx, order.ID); err != nil {
+			return nil, fmt.Errorf("failed to purge events of order %s: %v", order.ID, err)
+		}
+		orderIDs = append(orderIDs, order.ID)
+		result.OrdersPseudonymized++
+	}
+
+	addresses, err := s.addresses.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load addresses: %v", err)
+	}
+
+	for _, address := range addresses {
+		if err := s.addresses.Delete(ctx, address.ID); err != nil {
+			return nil, fmt.Errorf("failed to delete address %s: %v", address.ID, err)
+		}
+		result.AddressesDeleted++
+	}
+
+	if err := s.cache.DeleteDeliveryAddresses(ctx, userID); err != nil {
+		return nil, fmt.Errorf("failed to purge cached addresses: %v", err)
+	}
+
+	if err := s.publisher.PublishUserDataErased(ctx, userID, orderIDs); err != nil {
+		return nil, fmt.Errorf("failed to publish erasure event: %v", err)
+	}
+
+	return result, nil
+}
+
+// pseudonymize rewrites the order's address under the order lock, from a
+// copy read past the cache so concurrent changes to the order are kept.
+func (s *PrivacyService) pseudonymize(ctx context.Context, orderID string) error {
+	return s.locker.WithOrderLock(ctx, orderID, func(ctx context.Context, _ int64) error {
+		order, err := s.store.GetByID(ctx, orderID)
+		if err != nil {
+			return err
+		}
+		order.PseudonymizeDeliveryAddress()
+		return s.orders.Update(ctx, order)
+	})
+}
+
+func (s *PrivacyService) allOrders(ctx context.Context, userID string) ([]*domain.Order, error) {
+	var orders []*domain.Order
+	for page := 1; ; page++ {
+		batch, total, err := s.orders.GetByUserID(ctx, userID, page, privacyBatchSize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load orders: %v", err)
+		}
+
+		orders = append(orders, batch...)
+		if len(batch) == 0 || len(orders) >= total {
+			return orders, nil
+		}
+	}
+}
@@ -0,0 +1,276 @@
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+// fakeOrderRepository stores orders in memory and records writes made
+// without the order lock held.
+type fakeOrderRepository struct {
+	domain.OrderRepository
+
+	mu       sync.Mutex
+	orders   []*domain.Order
+	locker   *fakeOrderLocker
+	pages    []int
+	unlocked []string
+}
+
+func (r *fakeOrderRepository) GetByID(ctx context.Context, id string) (*domain.Order, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, order := range r.orders {
+		if order.ID == id {
+			clone := *order
+			address := *order.DeliveryAddress
+			clone.DeliveryAddress = &address
+			return &clone, nil
+		}
+	}
+	return nil, domain.ErrInvalidOrderID
+}
+
+func (r *fakeOrderRepository) GetByUserID(ctx context.Context, userID string, page, limit int) ([]*domain.Order, int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.pages = append(r.pages, page)
+
+	var owned []*domain.Order
+	for _, order := range r.orders {
+		if order.UserID == userID {
+			owned = append(owned, order)
+		}
+	}
+	from := min((page-1)*limit, len(owned))
+	to := min(from+limit, len(owned))
+	return owned[from:to], len(owned), nil
+}
+
+func (r *fakeOrderRepository) Update(ctx context.Context, order *domain.Order) error {
+	if !r.locker.holds(order.ID) {
+		r.mu.Lock()
+		r.unlocked = append(r.unlocked, order.ID)
+		r.mu.Unlock()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i, stored := range r.orders {
+		if stored.ID == order.ID {
+			r.orders[i] = order
+			return nil
+		}
+	}
+	return domain.ErrInvalidOrderID
+}
+
+type fakeOrderLocker struct {
+	mu   sync.Mutex
+	held map[string]bool
+}
+
+func (l *fakeOrderLocker) WithOrderLock(ctx context.Context, orderID string, fn func(ctx context.Context, token int64) error) error {
+	l.mu.Lock()
+	l.held[orderID] = true
+	l.mu.Unlock()
+	defer func() {
+		l.mu.Lock()
+		delete(l.held, orderID)
+		l.mu.Unlock()
+	}()
+	return fn(ctx, 1)
+}
+
+func (l *fakeOrderLocker) holds(orderID string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.held[orderID]
+}
+
+type fakeAddressRepository struct {
+	domain.DeliveryAddressRepository
+
+	addresses []*domain.DeliveryAddress
+	deleted   []string
+}
+
+func (r *fakeAddressRepository) GetByUserID(ctx context.Context, userID string) ([]*domain.DeliveryAddress, error) {
+	var owned []*domain.DeliveryAddress
+	for _, address := range r.addresses {
+		if address.UserID == userID {
+			owned = append(owned, address)
+		}
+	}
+	return owned, nil
+}
+
+func (r *fakeAddressRepository) Delete(ctx context.Context, id string) error {
+	r.deleted = append(r.deleted, id)
+	return nil
+}
+
+// fakeUserDataCache records purges of cached user data and published
+// events.
+type fakeUserDataCache struct {
+	orders       []string
+	addressUsers []string
+	events       []string
+}
+
+func (c *fakeUserDataCache) DeleteOrder(ctx context.Context, orderID string) error {
+	c.orders = append(c.orders, orderID)
+	return nil
+}
+
+func (c *fakeUserDataCache) DeleteDeliveryAddresses(ctx context.Context, userID string) error {
+	c.addressUsers = append(c.addressUsers, userID)
+	return nil
+}
+
+func (c *fakeUserDataCache) PurgeOrderEvents(ctx context.Context, orderID string) error {
+	c.events = append(c.events, orderID)
+	return nil
+}
+
+type privacyFixture struct {
+	service   *PrivacyService
+	orders    *fakeOrderRepository
+	addresses *fakeAddressRepository
+	cache     *fakeUserDataCache
+	publisher *fakePublisher
+	orderIDs  []string
+}
+
+// newPrivacyFixture stores count orders of user-1, enough to span several
+// pages, one order of user-2 and a saved address for each user.
+func newPrivacyFixture(count int) *privacyFixture {
+	locker := &fakeOrderLocker{held: map[string]bool{}}
+	f := &privacyFixture{
+		orders: &fakeOrderRepository{locker: locker},
+		addresses: &fakeAddressRepository{addresses: []*domain.DeliveryAddress{
+			{ID: "address-1", UserID: "user-1", FullName: "Jane Roe"},
+			{ID: "address-2", UserID: "user-2", FullName: "John Doe"},
+		}},
+		cache:     &fakeUserDataCache{},
+		publisher: &fakePublisher{},
+	}
+
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("order-%03d", i)
+		f.orderIDs = append(f.orderIDs, id)
+		f.orders.orders = append(f.orders.orders, &domain.Order{
+			ID:         id,
+			UserID:     "user-1",
+			Items:      []domain.OrderItem{{ProductID: "p1", Quantity: 2, UnitPrice: 3, TotalPrice: 6}},
+			TotalPrice: 6,
+			DeliveryAddress: &domain.DeliveryAddress{
+				UserID:        "user-1",
+				FullName:      "Jane Roe",
+				StreetAddress: "221B Baker Street",
+				City:          "London",
+				Phone:         "+442079460958",
+			},
+		})
+	}
+	f.orders.orders = append(f.orders.orders, &domain.Order{
+		ID:              "other-order",
+		UserID:          "user-2",
+		DeliveryAddress: &domain.DeliveryAddress{UserID: "user-2", FullName: "John Doe"},
+	})
+
+	f.service = NewPrivacyService(f.orders, f.orders, locker, f.addresses, f.cache, f.publisher, f.cache)
+	return f
+}
+
+func TestExportUserDataWalksAllPages(t *testing.T) {
+	f := newPrivacyFixture(2*privacyBatchSize + 1)
+
+	export, err := f.service.ExportUserData(context.Background(), "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(export.Orders) != len(f.orderIDs) {
+		t.Errorf("exported %d orders, want %d", len(export.Orders), len(f.orderIDs))
+	}
+	for _, order := range export.Orders {
+		if order.UserID != "user-1" {
+			t.Errorf("exported order %s of %s", order.ID, order.UserID)
+		}
+	}
+	if want := []int{1, 2, 3}; !reflect.DeepEqual(f.orders.pages, want) {
+		t.Errorf("read pages %v, want %v", f.orders.pages, want)
+	}
+	if len(export.Addresses) != 1 || export.Addresses[0].ID != "address-1" {
+		t.Errorf("exported addresses %+v, want address-1", export.Addresses)
+	}
+
+	if _, err := f.service.ExportUserData(context.Background(), ""); err != domain.ErrInvalidUserID {
+		t.Errorf("export without user = %v, want ErrInvalidUserID", err)
+	}
+}
+
+func TestEraseUserData(t *testing.T) {
+	f := newPrivacyFixture(privacyBatchSize + 5)
+	ctx := context.Background()
+
+	result, err := f.service.EraseUserData(ctx, "user-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.OrdersPseudonymized != len(f.orderIDs) || result.AddressesDeleted != 1 {
+		t.Errorf("result = %+v, want %d orders and 1 address", result, len(f.orderIDs))
+	}
+
+	for _, id := range f.orderIDs {
+		order, err := f.orders.GetByID(ctx, id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if order.DeliveryAddress.FullName != domain.ErasedFullName || order.DeliveryAddress.Phone != "" {
+			t.Errorf("order %s keeps personal data: %+v", id, order.DeliveryAddress)
+		}
+		if order.TotalPrice != 6 || len(order.Items) != 1 {
+			t.Errorf("order %s lost its financial record: %+v", id, order)
+		}
+	}
+	if other, _ := f.orders.GetByID(ctx, "other-order"); other.DeliveryAddress.FullName != "John Doe" {
+		t.Error("another user's order was pseudonymized")
+	}
+	if len(f.orders.unlocked) > 0 {
+		t.Errorf("orders %v were written without the order lock", f.orders.unlocked)
+	}
+
+	sorted := func(ids []string) []string {
+		ids = append([]string(nil), ids...)
+		sort.Strings(ids)
+		return ids
+	}
+	if got := sorted(f.cache.orders); !reflect.DeepEqual(got, f.orderIDs) {
+		t.Errorf("purged cached orders %v, want %v", got, f.orderIDs)
+	}
+	if got := sorted(f.cache.events); !reflect.DeepEqual(got, f.orderIDs) {
+		t.Errorf("purged events of %v, want %v", got, f.orderIDs)
+	}
+	if !reflect.DeepEqual(f.cache.addressUsers, []string{"user-1"}) {
+		t.Errorf("purged cached addresses of %v, want [user-1]", f.cache.addressUsers)
+	}
+	if !reflect.DeepEqual(f.addresses.deleted, []string{"address-1"}) {
+		t.Errorf("deleted addresses %v, want [address-1]", f.addresses.deleted)
+	}
+
+	events := f.publisher.published()
+	if len(events) != 1 || events[0].Kind != "UserDataErased" || events[0].UserID != "user-1" {
+		t.Fatalf("published %+v, want one UserDataErased for user-1", events)
+	}
+	if got := sorted(events[0].OrderIDs); !reflect.DeepEqual(got, f.orderIDs) {
+		t.Errorf("erasure event lists %v, want %v", got, f.orderIDs)
+	}
+}
@@ -0,0 +1,429 @@
+package usecase
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+	"github.com/hsibAD/order-service/internal/logging"
+	"github.com/hsibAD/order-service/internal/metrics"
+	"go.uber.org/zap"
+)
+
+// Slot IDs are the UTC start time of the slot
+const slotIDLayout = "20060102T1504Z"
+
+// SlotSchedule describes the delivery windows offered every day (UTC).
+type SlotSchedule struct {
+	FirstHour  int // Start of the first slot
+	LastHour   int // End of the last slot
+	SlotLength time.Duration
+	Capacity   int
+	HoldTTL    time.Duration
+	CacheTTL   time.Duration
+}
+
+// SlotLocker serializes capacity checks for a single slot across replicas.
+// fn gets the lock's fencing token, which slot writes pass to the repository.
+type SlotLocker interface {
+	WithSlotLock(ctx context.Context, slotID string, fn func(ctx context.Context, token int64) error) error
+}
+
+type SlotCache interface {
+	GetDeliverySlots(ctx context.Context, date string) ([]*domain.DeliverySlot, error)
+	SetDeliverySlots(ctx context.Context, date string, slots []*domain.DeliverySlot, ttl time.Duration) error
+	DeleteDeliverySlots(ctx context.Context, date string) error
+}
+
+// SlotService hands out delivery slot capacity. A slot's booked count is the
+// number of orders reserved in it plus the checkout holds still alive.
+type SlotService struct {
+	schedule  SlotSchedule
+	pricing   domain.DeliveryPricing
+	slots     domain.DeliverySlotRepository
+	holds     domain.SlotHoldRepository
+	locker    SlotLocker
+	cache     SlotCache
+	publisher domain.EventPublisher
+}
+
+func NewSlotService(
+	schedule SlotSchedule,
+	pricing domain.DeliveryPricing,
+	slots domain.DeliverySlotRepository,
+	holds domain.SlotHoldRepository,
+	locker SlotLocker,
+	cache SlotCache,
+	publisher domain.EventPublisher,
+) *SlotService {
+	return &SlotService{
+		schedule:  schedule,
+		pricing:   pricing,
+		slots:     slots,
+		holds:     holds,
+		locker:    locker,
+		cache:     cache,
+		publisher: publisher,
+	}
+}
+
+// GetAvailableSlots lists the day's slots with their current bookings and
+// delivery fees.
+func (s *SlotService) GetAvailableSlots(ctx context.Context, date time.Time) ([]*domain.DeliverySlot, error) {
+	day := dateKey(date)
+
+	slots, err := s.cache.GetDeliverySlots(ctx, day)
+	if err != nil || slots == nil {
+		slots = s.slotsForDate(date)
+		if err := s.loadBookings(ctx, slots); err != nil {
+			return nil, err
+		}
+		_ = s.cache.SetDeliverySlots(ctx, day, slots, s.schedule.CacheTTL)
+	}
+
+	// Availability also depends on the clock, so it is never served from
+	// cache. Cached slots may be shared with other requests; work on copies.
+	now := time.Now()
+	result := make([]*domain.DeliverySlot, len(slots))
+	for i, slot := range slots {
+		slot := *slot
+		slot.Available = slot.Booked < slot.Capacity && slot.StartTime.After(now)
+		slot.SurgeMultiplier = s.pricing.SurgeFor(&slot)
+		slot.DeliveryFee = s.pricing.SlotFee(&slot)
+		result[i] = &slot
+	}
+
+	return result, nil
+}
+
+func (s *SlotService) Pricing() domain.DeliveryPricing {
+	return s.pricing
+}
+
+// QuoteDelivery prices delivering a basket worth itemsTotal to postalCode in
+// the slot starting at deliveryTime, based on the slot's current bookings.
+func (s *SlotService) QuoteDelivery(ctx context.Context, deliveryTime time.Time, postalCode string, itemsTotal float64) (float64, error) {
+	slot, err := s.parseSlotID(s.SlotID(deliveryTime))
+	if err != nil {
+		return 0, domain.ErrInvalidDeliveryTime
+	}
+
+	if err := s.loadBookings(ctx, []*domain.DeliverySlot{slot}); err != nil {
+		return 0, err
+	}
+
+	return s.pricing.OrderDeliveryFee(slot, postalCode, itemsTotal)
+}
+
+// HoldSlot places a checkout hold on slotID that counts against its capacity
+// until it is consumed by CreateOrder or expires.
+func (s *SlotService) HoldSlot(ctx context.Context, userID string, slotID string) (*domain.SlotHold, error) {
+	if userID == "" {
+		return nil, domain.ErrInvalidUserID
+	}
+
+	slot, err := s.parseSlotID(slotID)
+	if err != nil {
+		return nil, err
+	}
+
+	if !slot.StartTime.After(time.Now()) {
+		return nil, domain.ErrInvalidDeliveryTime
+	}
+
+	token, err := newHoldToken()
+	if err != nil {
+		return nil, err
+	}
+
+	hold := &domain.SlotHold{
+		Token:  token,
+		SlotID: slot.ID,
+		UserID: userID,
+	}
+
+	err = s.locker.WithSlotLock(ctx, slot.ID, func(ctx context.Context, fence int64) error {
+		if err := s.ensureCapacity(ctx, slot, fence); err != nil {
+			return err
+		}
+
+		hold.ExpiresAt = time.Now().Add(s.schedule.HoldTTL)
+		return s.createHold(ctx, hold, fence)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidate(ctx, slot.StartTime)
+	return hold, nil
+}
+
+// GetHold returns a live hold owned by userID.
+func (s *SlotService) GetHold(ctx context.Context, userID string, token string) (*domain.SlotHold, error) {
+	hold, err := s.holds.GetByToken(ctx, token)
+	if err != nil {
+		return nil, err
+	}
+
+	if hold.UserID != userID || hold.IsExpired(time.Now()) {
+		return nil, domain.ErrInvalidHoldToken
+	}
+
+	return hold, nil
+}
+
+// ReserveSlot books the slot starting at deliveryTime for an order. When a
+// hold token is given its capacity is taken over instead of being checked
+// again.
+func (s *SlotService) ReserveSlot(ctx context.Context, orderID string, userID string, deliveryTime time.Time, holdToken string) error {
+	slot, err := s.parseSlotID(s.SlotID(deliveryTime))
+	if err != nil {
+		return err
+	}
+
+	err = s.locker.WithSlotLock(ctx, slot.ID, func(ctx context.Context, fence int64) error {
+		if holdToken != "" {
+			hold, err := s.GetHold(ctx, userID, holdToken)
+			if err != nil {
+				return err
+			}
+			if hold.SlotID != slot.ID {
+				return domain.ErrHoldSlotMismatch
+			}
+			// Deleting claims the hold, so the sweeper can't also release it
+			if err := s.holds.Delete(ctx, holdToken); err != nil {
+				return err
+			}
+			if err := s.slots.ReserveSlot(ctx, orderID, slot.ID, fence); err != nil {
+				if restoreErr := s.createHold(ctx, hold, fence); restoreErr != nil {
+					logging.FromContext(ctx).Warn("failed to restore slot hold",
+						zap.String("slot_id", slot.ID), zap.Error(restoreErr))
+				}
+				return err
+			}
+			return nil
+		}
+
+		if err := s.ensureCapacity(ctx, slot, fence); err != nil {
+			return err
+		}
+		return s.slots.ReserveSlot(ctx, orderID, slot.ID, fence)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, slot.StartTime)
+	return nil
+}
+
+func (s *SlotService) ReleaseSlot(ctx context.Context, orderID string, deliveryTime time.Time) error {
+	if err := s.slots.ReleaseSlot(ctx, orderID, s.SlotID(deliveryTime)); err != nil {
+		return err
+	}
+
+	s.invalidate(ctx, deliveryTime)
+	return nil
+}
+
+// ReleaseExpiredHolds frees the capacity of every expired hold and announces
+// each one with a DeliverySlotHoldExpired event.
+func (s *SlotService) ReleaseExpiredHolds(ctx context.Context) (int, error) {
+	released := 0
+	for {
+		hold, err := s.holds.ClaimExpired(ctx, time.Now())
+		if err != nil {
+			return released, err
+		}
+		if hold == nil {
+			return released, nil
+		}
+		released++
+
+		if slot, err := s.parseSlotID(hold.SlotID); err == nil {
+			s.invalidate(ctx, slot.StartTime)
+		}
+
+		if err := s.publisher.PublishDeliverySlotHoldExpired(ctx, hold); err != nil {
+			return released, fmt.Errorf("failed to publish hold expiry: %v", err)
+		}
+	}
+}
+
+// RunHoldSweeper releases expired holds every interval until ctx is done.
+func (s *SlotService) RunHoldSweeper(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			released, err := s.ReleaseExpiredHolds(ctx)
+			if err != nil {
+				logging.FromContext(ctx).Error("failed to release expired slot holds", zap.Error(err))
+			}
+			if released > 0 {
+				logging.FromContext(ctx).Info("released expired slot holds", zap.Int("count", released))
+			}
+		}
+	}
+}
+
+// RunUtilizationReporter refreshes the utilization gauges of today's slots
+// every interval until ctx is done.
+func (s *SlotService) RunUtilizationReporter(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.reportUtilization(ctx); err != nil {
+			logging.FromContext(ctx).Warn("failed to report slot utilization", zap.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *SlotService) reportUtilization(ctx context.Context) error {
+	slots, err := s.GetAvailableSlots(ctx, time.Now())
+	if err != nil {
+		return err
+	}
+
+	for _, slot := range slots {
+		label := slot.StartTime.UTC().Format("15:04")
+		metrics.SlotBooked.WithLabelValues(label).Set(float64(slot.Booked))
+		if slot.Capacity > 0 {
+			metrics.SlotUtilization.WithLabelValues(label).Set(float64(slot.Booked) / float64(slot.Capacity))
+		}
+	}
+	return nil
+}
+
+func (s *SlotService) SlotID(start time.Time) string {
+	return start.UTC().Format(slotIDLayout)
+}
+
+// parseSlotID rebuilds the slot from its ID and rejects IDs that don't line
+// up with the schedule.
+func (s *SlotService) parseSlotID(slotID string) (*domain.DeliverySlot, error) {
+	start, err := time.Parse(slotIDLayout, slotID)
+	if err != nil {
+		return nil, domain.ErrInvalidSlotID
+	}
+
+	for _, slot := range s.slotsForDate(start) {
+		if slot.StartTime.Equal(start) {
+			return slot, nil
+		}
+	}
+
+	return nil, domain.ErrInvalidSlotID
+}
+
+func (s *SlotService) slotsForDate(date time.Time) []*domain.DeliverySlot {
+	y, m, d := date.UTC().Date()
+	first := time.Date(y, m, d, s.schedule.FirstHour, 0, 0, 0, time.UTC)
+	last := time.Date(y, m, d, s.schedule.LastHour, 0, 0, 0, time.UTC)
+
+	var slots []*domain.DeliverySlot
+	for start := first; !start.Add(s.schedule.SlotLength).After(last); start = start.Add(s.schedule.SlotLength) {
+		slots = append(slots, &domain.DeliverySlot{
+			ID:        s.SlotID(start),
+			StartTime: start,
+			EndTime:   start.Add(s.schedule.SlotLength),
+			Capacity:  s.schedule.Capacity,
+		})
+	}
+
+	return slots
+}
+
+func (s *SlotService) loadBookings(ctx context.Context, slots []*domain.DeliverySlot) error {
+	ids := make([]string, len(slots))
+	for i, slot := range slots {
+		ids[i] = slot.ID
+	}
+
+	reserved, err := s.slots.GetReservationCounts(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("failed to load reservations: %v", err)
+	}
+
+	held, err := s.holds.CountActive(ctx, ids, time.Now())
+	if err != nil {
+		return fmt.Errorf("failed to load holds: %v", err)
+	}
+
+	for _, slot := range slots {
+		slot.Booked = reserved[slot.ID] + held[slot.ID]
+	}
+
+	return nil
+}
+
+// ensureCapacity must be called under the slot lock. It fences the slot
+// before counting, so every write a stale holder still gets in is counted.
+func (s *SlotService) ensureCapacity(ctx context.Context, slot *domain.DeliverySlot, fence int64) error {
+	if err := s.slots.Fence(ctx, slot.ID, fence); err != nil {
+		return err
+	}
+
+	if err := s.loadBookings(ctx, []*domain.DeliverySlot{slot}); err != nil {
+		return err
+	}
+
+	if slot.Booked >= slot.Capacity {
+		return domain.ErrSlotUnavailable
+	}
+
+	return nil
+}
+
+// createHold stores hold under the slot lock. Holds live outside the slot
+// document, so the fence is checked again after the insert and the hold is
+// taken back if a newer lock holder fenced the slot in the meantime.
+func (s *SlotService) createHold(ctx context.Context, hold *domain.SlotHold, fence int64) error {
+	if err := s.holds.Create(ctx, hold); err != nil {
+		return err
+	}
+
+	if err := s.slots.Fence(ctx, hold.SlotID, fence); err != nil {
+		if deleteErr := s.holds.Delete(ctx, hold.Token); deleteErr != nil {
+			logging.FromContext(ctx).Warn("failed to remove slot hold of stale lock",
+				zap.String("slot_id", hold.SlotID), zap.Error(deleteErr))
+		}
+		return err
+	}
+
+	return nil
+}
+
+func (s *SlotService) invalidate(ctx context.Context, date time.Time) {
+	// Entries also expire on their own, so a failed delete only delays updates
+	if err := s.cache.DeleteDeliverySlots(ctx, dateKey(date)); err != nil {
+		logging.FromContext(ctx).Warn("failed to invalidate cached slots",
+			zap.String("date", dateKey(date)), zap.Error(err))
+	}
+}
+
+func dateKey(date time.Time) string {
+	return date.UTC().Format("2006-01-02")
+}
+
+func newHoldToken() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,337 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/hsibAD/order-service/internal/domain"
+)
+
+type fakeSlotRepository struct {
+	mu        sync.Mutex
+	orders    map[string][]string
+	fences    map[string]int64
+	reserveFn func(slotID string) error // Injected ReserveSlot failure
+}
+
+func newFakeSlotRepository() *fakeSlotRepository {
+	return &fakeSlotRepository{orders: map[string][]string{}, fences: map[string]int64{}}
+}
+
+func (r *fakeSlotRepository) GetReservationCounts(ctx context.Context, slotIDs []string) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := map[string]int{}
+	for _, id := range slotIDs {
+		counts[id] = len(r.orders[id])
+	}
+	return counts, nil
+}
+
+func (r *fakeSlotRepository) ReserveSlot(ctx context.Context, orderID string, slotID string, fence int64) error {
+	if r.reserveFn != nil {
+		if err := r.reserveFn(slotID); err != nil {
+			return err
+		}
+	}
+	if err := r.Fence(ctx, slotID, fence); err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.orders[slotID] = append(r.orders[slotID], orderID)
+	return nil
+}
+
+func (r *fakeSlotRepository) Fence(ctx context.Context, slotID string, fence int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.fences[slotID] > fence {
+		return domain.ErrStaleLock
+	}
+	r.fences[slotID] = fence
+	return nil
+}
+
+func (r *fakeSlotRepository) ReleaseSlot(ctx context.Context, orderID string, slotID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	kept := r.orders[slotID][:0]
+	for _, id := range r.orders[slotID] {
+		if id != orderID {
+			kept = append(kept, id)
+		}
+	}
+	r.orders[slotID] = kept
+	return nil
+}
+
+type fakeHoldRepository struct {
+	mu    sync.Mutex
+	holds map[string]domain.SlotHold
+}
+
+func newFakeHoldRepository() *fakeHoldRepository {
+	return &fakeHoldRepository{holds: map[string]domain.SlotHold{}}
+}
+
+func (r *fakeHoldRepository) Create(ctx context.Context, hold *domain.SlotHold) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.holds[hold.Token] = *hold
+	return nil
+}
+
+func (r *fakeHoldRepository) GetByToken(ctx context.Context, token string) (*domain.SlotHold, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	hold, ok := r.holds[token]
+	if !ok {
+		return nil, domain.ErrInvalidHoldToken
+	}
+	return &hold, nil
+}
+
+func (r *fakeHoldRepository) Delete(ctx context.Context, token string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.holds[token]; !ok {
+		return domain.ErrInvalidHoldToken
+	}
+	delete(r.holds, token)
+	return nil
+}
+
+func (r *fakeHoldRepository) CountActive(ctx context.Context, slotIDs []string, now time.Time) (map[string]int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	counts := map[string]int{}
+	for _, id := range slotIDs {
+		for _, hold := range r.holds {
+			if hold.SlotID == id && !hold.IsExpired(now) {
+				counts[id]++
+			}
+		}
+	}
+	return counts, nil
+}
+
+func (r *fakeHoldRepository) ClaimExpired(ctx context.Context, now time.Time) (*domain.SlotHold, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for token, hold := range r.holds {
+		if hold.IsExpired(now) {
+			delete(r.holds, token)
+			return &hold, nil
+		}
+	}
+	return nil, nil
+}
+
+// expire backdates every hold.
+func (r *fakeHoldRepository) expire() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for token, hold := range r.holds {
+		hold.ExpiresAt = time.Now().Add(-time.Second)
+		r.holds[token] = hold
+	}
+}
+
+// fakeSlotLocker hands out increasing fencing tokens, like the Redis lock.
+type fakeSlotLocker struct {
+	mu    sync.Mutex
+	token int64
+}
+
+func (l *fakeSlotLocker) WithSlotLock(ctx context.Context, slotID string, fn func(ctx context.Context, token int64) error) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.token++
+	return fn(ctx, l.token)
+}
+
+type fakeSlotCache struct{}
+
+func (fakeSlotCache) GetDeliverySlots(ctx context.Context, date string) ([]*domain.DeliverySlot, error) {
+	return nil, nil
+}
+
+func (fakeSlotCache) SetDeliverySlots(ctx context.Context, date string, slots []*domain.DeliverySlot, ttl time.Duration) error {
+	return nil
+}
+
+func (fakeSlotCache) DeleteDeliverySlots(ctx context.Context, date string) error {
+	return nil
+}
+
+type slotFixture struct {
+	service   *SlotService
+	slots     *fakeSlotRepository
+	holds     *fakeHoldRepository
+	publisher *fakePublisher
+	start     time.Time
+	slotID    string
+}
+
+func newSlotFixture(capacity int) *slotFixture {
+	f := &slotFixture{
+		slots:     newFakeSlotRepository(),
+		holds:     newFakeHoldRepository(),
+		publisher: &fakePublisher{},
+	}
+	schedule := SlotSchedule{
+		FirstHour:  0,
+		LastHour:   24,
+		SlotLength: time.Hour,
+		Capacity:   capacity,
+		HoldTTL:    10 * time.Minute,
+	}
+	f.service = NewSlotService(schedule, domain.DeliveryPricing{}, f.slots, f.holds, &fakeSlotLocker{}, fakeSlotCache{}, f.publisher)
+	f.start = time.Now().UTC().Add(24 * time.Hour).Truncate(time.Hour)
+	f.slotID = f.service.SlotID(f.start)
+	return f
+}
+
+func (f *slotFixture) booked(t *testing.T) int {
+	t.Helper()
+	slot, err := f.service.parseSlotID(f.slotID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.service.loadBookings(context.Background(), []*domain.DeliverySlot{slot}); err != nil {
+		t.Fatal(err)
+	}
+	return slot.Booked
+}
+
+func TestSlotCapacityIsExhausted(t *testing.T) {
+	ctx := context.Background()
+	f := newSlotFixture(2)
+
+	if _, err := f.service.HoldSlot(ctx, "user-1", f.slotID); err != nil {
+		t.Fatalf("HoldSlot: %v", err)
+	}
+	if err := f.service.ReserveSlot(ctx, "order-1", "user-2", f.start, ""); err != nil {
+		t.Fatalf("ReserveSlot: %v", err)
+	}
+
+	if _, err := f.service.HoldSlot(ctx, "user-3", f.slotID); !errors.Is(err, domain.ErrSlotUnavailable) {
+		t.Errorf("HoldSlot on a full slot = %v, want ErrSlotUnavailable", err)
+	}
+	if err := f.service.ReserveSlot(ctx, "order-2", "user-3", f.start, ""); !errors.Is(err, domain.ErrSlotUnavailable) {
+		t.Errorf("ReserveSlot on a full slot = %v, want ErrSlotUnavailable", err)
+	}
+	if got := f.booked(t); got != 2 {
+		t.Errorf("booked = %d, want 2", got)
+	}
+}
+
+func TestExpiredHoldsFreeCapacity(t *testing.T) {
+	ctx := context.Background()
+	f := newSlotFixture(1)
+
+	hold, err := f.service.HoldSlot(ctx, "user-1", f.slotID)
+	if err != nil {
+		t.Fatalf("HoldSlot: %v", err)
+	}
+	f.holds.expire()
+
+	if _, err := f.service.GetHold(ctx, "user-1", hold.Token); !errors.Is(err, domain.ErrInvalidHoldToken) {
+		t.Errorf("GetHold of an expired hold = %v, want ErrInvalidHoldToken", err)
+	}
+	if got := f.booked(t); got != 0 {
+		t.Errorf("booked with an expired hold = %d, want 0", got)
+	}
+
+	released, err := f.service.ReleaseExpiredHolds(ctx)
+	if err != nil || released != 1 {
+		t.Fatalf("ReleaseExpiredHolds = %d, %v; want 1, nil", released, err)
+	}
+	events := f.publisher.published()
+	if len(events) != 1 || events[0].Kind != "DeliverySlotHoldExpired" || events[0].Hold.Token != hold.Token {
+		t.Errorf("published %+v, want one DeliverySlotHoldExpired for the hold", events)
+	}
+
+	if err := f.service.ReserveSlot(ctx, "order-1", "user-1", f.start, hold.Token); !errors.Is(err, domain.ErrInvalidHoldToken) {
+		t.Errorf("ReserveSlot with a released hold = %v, want ErrInvalidHoldToken", err)
+	}
+	if _, err := f.service.HoldSlot(ctx, "user-2", f.slotID); err != nil {
+		t.Errorf("HoldSlot after expiry: %v", err)
+	}
+}
+
+func TestReserveSlotConsumesHold(t *testing.T) {
+	ctx := context.Background()
+	f := newSlotFixture(1)
+
+	hold, err := f.service.HoldSlot(ctx, "user-1", f.slotID)
+	if err != nil {
+		t.Fatalf("HoldSlot: %v", err)
+	}
+
+	if err := f.service.ReserveSlot(ctx, "order-1", "user-2", f.start, hold.Token); !errors.Is(err, domain.ErrInvalidHoldToken) {
+		t.Errorf("ReserveSlot with another user's hold = %v, want ErrInvalidHoldToken", err)
+	}
+	if err := f.service.ReserveSlot(ctx, "order-1", "user-1", f.start.Add(time.Hour), hold.Token); !errors.Is(err, domain.ErrHoldSlotMismatch) {
+		t.Errorf("ReserveSlot in another slot = %v, want ErrHoldSlotMismatch", err)
+	}
+
+	// The slot is full, but the hold's capacity is taken over
+	if err := f.service.ReserveSlot(ctx, "order-1", "user-1", f.start, hold.Token); err != nil {
+		t.Fatalf("ReserveSlot with hold: %v", err)
+	}
+	if got := f.booked(t); got != 1 {
+		t.Errorf("booked = %d, want 1: the hold must not be counted next to the order", got)
+	}
+	if err := f.service.ReserveSlot(ctx, "order-2", "user-1", f.start, hold.Token); !errors.Is(err, domain.ErrInvalidHoldToken) {
+		t.Errorf("reusing a consumed hold = %v, want ErrInvalidHoldToken", err)
+	}
+}
+
+func TestReserveSlotRestoresHoldOnFailure(t *testing.T) {
+	ctx := context.Background()
+	f := newSlotFixture(1)
+
+	hold, err := f.service.HoldSlot(ctx, "user-1", f.slotID)
+	if err != nil {
+		t.Fatalf("HoldSlot: %v", err)
+	}
+
+	failure := errors.New("write failed")
+	f.slots.reserveFn = func(string) error { return failure }
+	if err := f.service.ReserveSlot(ctx, "order-1", "user-1", f.start, hold.Token); !errors.Is(err, failure) {
+		t.Fatalf("ReserveSlot = %v, want %v", err, failure)
+	}
+	if _, err := f.service.GetHold(ctx, "user-1", hold.Token); err != nil {
+		t.Errorf("hold was not restored: %v", err)
+	}
+	if got := f.booked(t); got != 1 {
+		t.Errorf("booked = %d, want 1", got)
+	}
+
+	f.slots.reserveFn = nil
+	if err := f.service.ReserveSlot(ctx, "order-1", "user-1", f.start, hold.Token); err != nil {
+		t.Errorf("retrying ReserveSlot: %v", err)
+	}
+}
+
+func TestStaleLockHolderCannotAddHold(t *testing.T) {
+	ctx := context.Background()
+	f := newSlotFixture(1)
+
+	// A newer lock holder fenced the slot while this one was paused
+	hold := &domain.SlotHold{Token: "stale", SlotID: f.slotID, UserID: "user-1", ExpiresAt: time.Now().Add(time.Minute)}
+	if err := f.slots.Fence(ctx, f.slotID, 5); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.service.createHold(ctx, hold, 4); !errors.Is(err, domain.ErrStaleLock) {
+		t.Errorf("createHold with a stale fence = %v, want ErrStaleLock", err)
+	}
+	if got := f.booked(t); got != 0 {
+		t.Errorf("booked = %d, want 0", got)
+	}
+}
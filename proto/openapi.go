@@ -0,0 +1,9 @@
+package proto
+
+import _ "embed"
+
+// OpenAPI is the OpenAPI v2 spec of the REST gateway, generated from
+// order.proto by protoc-gen-openapiv2.
+//
+//go:embed order.swagger.json
+var OpenAPI []byte
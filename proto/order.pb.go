@@ -7,6 +7,7 @@
 package proto
 
 import (
+	_ "google.golang.org/genproto/googleapis/api/annotations"
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
@@ -35,6 +36,8 @@ type Order struct {
 	DeliveryTime    *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=delivery_time,json=deliveryTime,proto3" json:"delivery_time,omitempty"`
 	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
 	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DeliveryFee     float64                `protobuf:"fixed64,11,opt,name=delivery_fee,json=deliveryFee,proto3" json:"delivery_fee,omitempty"` // Included in total_price
+	CourierId       string                 `protobuf:"bytes,12,opt,name=courier_id,json=courierId,proto3" json:"courier_id,omitempty"`
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
@@ -139,6 +142,20 @@ func (x *Order) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Order) GetDeliveryFee() float64 {
+	if x != nil {
+		return x.DeliveryFee
+	}
+	return 0
+}
+
+func (x *Order) GetCourierId() string {
+	if x != nil {
+		return x.CourierId
+	}
+	return ""
+}
+
 type OrderItem struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	ProductId     string                 `protobuf:"bytes,1,opt,name=product_id,json=productId,proto3" json:"product_id,omitempty"`
@@ -224,6 +241,12 @@ type DeliveryAddress struct {
 	State         string                 `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
 	Country       string                 `protobuf:"bytes,6,opt,name=country,proto3" json:"country,omitempty"`
 	PostalCode    string                 `protobuf:"bytes,7,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	FullName      string                 `protobuf:"bytes,8,opt,name=full_name,json=fullName,proto3" json:"full_name,omitempty"`
+	Apartment     string                 `protobuf:"bytes,9,opt,name=apartment,proto3" json:"apartment,omitempty"`
+	Phone         string                 `protobuf:"bytes,10,opt,name=phone,proto3" json:"phone,omitempty"`
+	IsDefault     bool                   `protobuf:"varint,11,opt,name=is_default,json=isDefault,proto3" json:"is_default,omitempty"`
+	Location      *GeoPoint              `protobuf:"bytes,12,opt,name=location,proto3" json:"location,omitempty"` // Set by the service when the address is geocoded
+	Preferences   *DeliveryPreferences   `protobuf:"bytes,13,opt,name=preferences,proto3" json:"preferences,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -307,18 +330,146 @@ func (x *DeliveryAddress) GetPostalCode() string {
 	return ""
 }
 
+func (x *DeliveryAddress) GetFullName() string {
+	if x != nil {
+		return x.FullName
+	}
+	return ""
+}
+
+func (x *DeliveryAddress) GetApartment() string {
+	if x != nil {
+		return x.Apartment
+	}
+	return ""
+}
+
+func (x *DeliveryAddress) GetPhone() string {
+	if x != nil {
+		return x.Phone
+	}
+	return ""
+}
+
+func (x *DeliveryAddress) GetIsDefault() bool {
+	if x != nil {
+		return x.IsDefault
+	}
+	return false
+}
+
+func (x *DeliveryAddress) GetLocation() *GeoPoint {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+func (x *DeliveryAddress) GetPreferences() *DeliveryPreferences {
+	if x != nil {
+		return x.Preferences
+	}
+	return nil
+}
+
+type DeliveryPreferences struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instructions  string                 `protobuf:"bytes,1,opt,name=instructions,proto3" json:"instructions,omitempty"`
+	LeaveAtDoor   bool                   `protobuf:"varint,2,opt,name=leave_at_door,json=leaveAtDoor,proto3" json:"leave_at_door,omitempty"`
+	Floor         string                 `protobuf:"bytes,3,opt,name=floor,proto3" json:"floor,omitempty"`
+	HasElevator   bool                   `protobuf:"varint,4,opt,name=has_elevator,json=hasElevator,proto3" json:"has_elevator,omitempty"`
+	ContactMethod string                 `protobuf:"bytes,5,opt,name=contact_method,json=contactMethod,proto3" json:"contact_method,omitempty"` // CALL, SMS or NO_CONTACT
+	AccessCode    string                 `protobuf:"bytes,6,opt,name=access_code,json=accessCode,proto3" json:"access_code,omitempty"`          // Only returned to couriers
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryPreferences) Reset() {
+	*x = DeliveryPreferences{}
+	mi := &file_order_service_proto_order_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryPreferences) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryPreferences) ProtoMessage() {}
+
+func (x *DeliveryPreferences) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryPreferences.ProtoReflect.Descriptor instead.
+func (*DeliveryPreferences) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *DeliveryPreferences) GetInstructions() string {
+	if x != nil {
+		return x.Instructions
+	}
+	return ""
+}
+
+func (x *DeliveryPreferences) GetLeaveAtDoor() bool {
+	if x != nil {
+		return x.LeaveAtDoor
+	}
+	return false
+}
+
+func (x *DeliveryPreferences) GetFloor() string {
+	if x != nil {
+		return x.Floor
+	}
+	return ""
+}
+
+func (x *DeliveryPreferences) GetHasElevator() bool {
+	if x != nil {
+		return x.HasElevator
+	}
+	return false
+}
+
+func (x *DeliveryPreferences) GetContactMethod() string {
+	if x != nil {
+		return x.ContactMethod
+	}
+	return ""
+}
+
+func (x *DeliveryPreferences) GetAccessCode() string {
+	if x != nil {
+		return x.AccessCode
+	}
+	return ""
+}
+
 type CreateOrderRequest struct {
 	state           protoimpl.MessageState `protogen:"open.v1"`
 	Items           []*OrderItem           `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
 	DeliveryAddress *DeliveryAddress       `protobuf:"bytes,2,opt,name=delivery_address,json=deliveryAddress,proto3" json:"delivery_address,omitempty"`
 	DeliveryTime    *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=delivery_time,json=deliveryTime,proto3" json:"delivery_time,omitempty"`
+	UserId          string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	HoldToken       string                 `protobuf:"bytes,5,opt,name=hold_token,json=holdToken,proto3" json:"hold_token,omitempty"` // From HoldDeliverySlot; delivery_time may then be omitted
 	unknownFields   protoimpl.UnknownFields
 	sizeCache       protoimpl.SizeCache
 }
 
 func (x *CreateOrderRequest) Reset() {
 	*x = CreateOrderRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[3]
+	mi := &file_order_service_proto_order_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -330,7 +481,7 @@ func (x *CreateOrderRequest) String() string {
 func (*CreateOrderRequest) ProtoMessage() {}
 
 func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[3]
+	mi := &file_order_service_proto_order_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -343,7 +494,7 @@ func (x *CreateOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateOrderRequest.ProtoReflect.Descriptor instead.
 func (*CreateOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{3}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *CreateOrderRequest) GetItems() []*OrderItem {
@@ -367,6 +518,20 @@ func (x *CreateOrderRequest) GetDeliveryTime() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *CreateOrderRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CreateOrderRequest) GetHoldToken() string {
+	if x != nil {
+		return x.HoldToken
+	}
+	return ""
+}
+
 type GetOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
@@ -376,7 +541,7 @@ type GetOrderRequest struct {
 
 func (x *GetOrderRequest) Reset() {
 	*x = GetOrderRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[4]
+	mi := &file_order_service_proto_order_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -388,7 +553,7 @@ func (x *GetOrderRequest) String() string {
 func (*GetOrderRequest) ProtoMessage() {}
 
 func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[4]
+	mi := &file_order_service_proto_order_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -401,7 +566,7 @@ func (x *GetOrderRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetOrderRequest.ProtoReflect.Descriptor instead.
 func (*GetOrderRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{4}
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *GetOrderRequest) GetOrderId() string {
@@ -411,29 +576,29 @@ func (x *GetOrderRequest) GetOrderId() string {
 	return ""
 }
 
-type UpdateOrderStatusRequest struct {
+type WatchOrderRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	SinceVersion  uint64                 `protobuf:"varint,2,opt,name=since_version,json=sinceVersion,proto3" json:"since_version,omitempty"` // Resume after this version; 0 starts with the current state
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateOrderStatusRequest) Reset() {
-	*x = UpdateOrderStatusRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[5]
+func (x *WatchOrderRequest) Reset() {
+	*x = WatchOrderRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateOrderStatusRequest) String() string {
+func (x *WatchOrderRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateOrderStatusRequest) ProtoMessage() {}
+func (*WatchOrderRequest) ProtoMessage() {}
 
-func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[5]
+func (x *WatchOrderRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -444,48 +609,50 @@ func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
-func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use WatchOrderRequest.ProtoReflect.Descriptor instead.
+func (*WatchOrderRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *UpdateOrderStatusRequest) GetOrderId() string {
+func (x *WatchOrderRequest) GetOrderId() string {
 	if x != nil {
 		return x.OrderId
 	}
 	return ""
 }
 
-func (x *UpdateOrderStatusRequest) GetStatus() string {
+func (x *WatchOrderRequest) GetSinceVersion() uint64 {
 	if x != nil {
-		return x.Status
+		return x.SinceVersion
 	}
-	return ""
+	return 0
 }
 
-type DeleteAddressRequest struct {
+type OrderUpdate struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AddressId     string                 `protobuf:"bytes,1,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Order         *Order                 `protobuf:"bytes,1,opt,name=order,proto3" json:"order,omitempty"`      // Unset on heartbeats
+	Version       uint64                 `protobuf:"varint,2,opt,name=version,proto3" json:"version,omitempty"` // Pass as since_version to resume after this update
+	Event         string                 `protobuf:"bytes,3,opt,name=event,proto3" json:"event,omitempty"`      // "Snapshot", "Heartbeat" or the event type that changed the order, e.g. "OrderStatusUpdated"
+	SentAt        *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=sent_at,json=sentAt,proto3" json:"sent_at,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteAddressRequest) Reset() {
-	*x = DeleteAddressRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[6]
+func (x *OrderUpdate) Reset() {
+	*x = OrderUpdate{}
+	mi := &file_order_service_proto_order_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteAddressRequest) String() string {
+func (x *OrderUpdate) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteAddressRequest) ProtoMessage() {}
+func (*OrderUpdate) ProtoMessage() {}
 
-func (x *DeleteAddressRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[6]
+func (x *OrderUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -496,90 +663,61 @@ func (x *DeleteAddressRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteAddressRequest.ProtoReflect.Descriptor instead.
-func (*DeleteAddressRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use OrderUpdate.ProtoReflect.Descriptor instead.
+func (*OrderUpdate) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *DeleteAddressRequest) GetAddressId() string {
+func (x *OrderUpdate) GetOrder() *Order {
 	if x != nil {
-		return x.AddressId
+		return x.Order
 	}
-	return ""
+	return nil
 }
 
-func (x *DeleteAddressRequest) GetUserId() string {
+func (x *OrderUpdate) GetVersion() uint64 {
 	if x != nil {
-		return x.UserId
+		return x.Version
 	}
-	return ""
-}
-
-type ListAddressesRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *ListAddressesRequest) Reset() {
-	*x = ListAddressesRequest{}
-	mi := &file_order_service_proto_order_proto_msgTypes[7]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
-}
-
-func (x *ListAddressesRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+	return 0
 }
 
-func (*ListAddressesRequest) ProtoMessage() {}
-
-func (x *ListAddressesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[7]
+func (x *OrderUpdate) GetEvent() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Event
 	}
-	return mi.MessageOf(x)
-}
-
-// Deprecated: Use ListAddressesRequest.ProtoReflect.Descriptor instead.
-func (*ListAddressesRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{7}
+	return ""
 }
 
-func (x *ListAddressesRequest) GetUserId() string {
+func (x *OrderUpdate) GetSentAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.UserId
+		return x.SentAt
 	}
-	return ""
+	return nil
 }
 
-type ListAddressesResponse struct {
+type UpdateOrderStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Addresses     []*DeliveryAddress     `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListAddressesResponse) Reset() {
-	*x = ListAddressesResponse{}
+func (x *UpdateOrderStatusRequest) Reset() {
+	*x = UpdateOrderStatusRequest{}
 	mi := &file_order_service_proto_order_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListAddressesResponse) String() string {
+func (x *UpdateOrderStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListAddressesResponse) ProtoMessage() {}
+func (*UpdateOrderStatusRequest) ProtoMessage() {}
 
-func (x *ListAddressesResponse) ProtoReflect() protoreflect.Message {
+func (x *UpdateOrderStatusRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_order_service_proto_order_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -591,40 +729,47 @@ func (x *ListAddressesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListAddressesResponse.ProtoReflect.Descriptor instead.
-func (*ListAddressesResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use UpdateOrderStatusRequest.ProtoReflect.Descriptor instead.
+func (*UpdateOrderStatusRequest) Descriptor() ([]byte, []int) {
 	return file_order_service_proto_order_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *ListAddressesResponse) GetAddresses() []*DeliveryAddress {
+func (x *UpdateOrderStatusRequest) GetOrderId() string {
 	if x != nil {
-		return x.Addresses
+		return x.OrderId
 	}
-	return nil
+	return ""
 }
 
-type SetDeliveryTimeRequest struct {
+func (x *UpdateOrderStatusRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+type AssignCourierRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
-	DeliveryTime  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=delivery_time,json=deliveryTime,proto3" json:"delivery_time,omitempty"`
+	CourierId     string                 `protobuf:"bytes,2,opt,name=courier_id,json=courierId,proto3" json:"courier_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SetDeliveryTimeRequest) Reset() {
-	*x = SetDeliveryTimeRequest{}
+func (x *AssignCourierRequest) Reset() {
+	*x = AssignCourierRequest{}
 	mi := &file_order_service_proto_order_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SetDeliveryTimeRequest) String() string {
+func (x *AssignCourierRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SetDeliveryTimeRequest) ProtoMessage() {}
+func (*AssignCourierRequest) ProtoMessage() {}
 
-func (x *SetDeliveryTimeRequest) ProtoReflect() protoreflect.Message {
+func (x *AssignCourierRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_order_service_proto_order_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -636,48 +781,549 @@ func (x *SetDeliveryTimeRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SetDeliveryTimeRequest.ProtoReflect.Descriptor instead.
-func (*SetDeliveryTimeRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use AssignCourierRequest.ProtoReflect.Descriptor instead.
+func (*AssignCourierRequest) Descriptor() ([]byte, []int) {
 	return file_order_service_proto_order_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SetDeliveryTimeRequest) GetOrderId() string {
+func (x *AssignCourierRequest) GetOrderId() string {
 	if x != nil {
 		return x.OrderId
 	}
 	return ""
 }
 
-func (x *SetDeliveryTimeRequest) GetDeliveryTime() *timestamppb.Timestamp {
+func (x *AssignCourierRequest) GetCourierId() string {
 	if x != nil {
-		return x.DeliveryTime
+		return x.CourierId
 	}
-	return nil
+	return ""
 }
 
-type DeliverySlotsRequest struct {
+type DeleteAddressRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	PostalCode    string                 `protobuf:"bytes,1,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
-	Date          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	AddressId     string                 `protobuf:"bytes,1,opt,name=address_id,json=addressId,proto3" json:"address_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeliverySlotsRequest) Reset() {
-	*x = DeliverySlotsRequest{}
+func (x *DeleteAddressRequest) Reset() {
+	*x = DeleteAddressRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAddressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAddressRequest) ProtoMessage() {}
+
+func (x *DeleteAddressRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_order_service_proto_order_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAddressRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAddressRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *DeleteAddressRequest) GetAddressId() string {
+	if x != nil {
+		return x.AddressId
+	}
+	return ""
+}
+
+func (x *DeleteAddressRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListAddressesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAddressesRequest) Reset() {
+	*x = ListAddressesRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAddressesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAddressesRequest) ProtoMessage() {}
+
+func (x *ListAddressesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAddressesRequest.ProtoReflect.Descriptor instead.
+func (*ListAddressesRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ListAddressesRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+type ListAddressesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addresses     []*DeliveryAddress     `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListAddressesResponse) Reset() {
+	*x = ListAddressesResponse{}
+	mi := &file_order_service_proto_order_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAddressesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAddressesResponse) ProtoMessage() {}
+
+func (x *ListAddressesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListAddressesResponse.ProtoReflect.Descriptor instead.
+func (*ListAddressesResponse) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ListAddressesResponse) GetAddresses() []*DeliveryAddress {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type GeoPoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Latitude      float64                `protobuf:"fixed64,1,opt,name=latitude,proto3" json:"latitude,omitempty"`
+	Longitude     float64                `protobuf:"fixed64,2,opt,name=longitude,proto3" json:"longitude,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GeoPoint) Reset() {
+	*x = GeoPoint{}
+	mi := &file_order_service_proto_order_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GeoPoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GeoPoint) ProtoMessage() {}
+
+func (x *GeoPoint) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GeoPoint.ProtoReflect.Descriptor instead.
+func (*GeoPoint) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *GeoPoint) GetLatitude() float64 {
+	if x != nil {
+		return x.Latitude
+	}
+	return 0
+}
+
+func (x *GeoPoint) GetLongitude() float64 {
+	if x != nil {
+		return x.Longitude
+	}
+	return 0
+}
+
+type CheckServiceabilityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostalCode    string                 `protobuf:"bytes,1,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Country       string                 `protobuf:"bytes,2,opt,name=country,proto3" json:"country,omitempty"`
+	Location      *GeoPoint              `protobuf:"bytes,3,opt,name=location,proto3" json:"location,omitempty"` // Optional, enables zone polygon checks
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CheckServiceabilityRequest) Reset() {
+	*x = CheckServiceabilityRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CheckServiceabilityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CheckServiceabilityRequest) ProtoMessage() {}
+
+func (x *CheckServiceabilityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CheckServiceabilityRequest.ProtoReflect.Descriptor instead.
+func (*CheckServiceabilityRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *CheckServiceabilityRequest) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *CheckServiceabilityRequest) GetCountry() string {
+	if x != nil {
+		return x.Country
+	}
+	return ""
+}
+
+func (x *CheckServiceabilityRequest) GetLocation() *GeoPoint {
+	if x != nil {
+		return x.Location
+	}
+	return nil
+}
+
+type ServiceabilityResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Serviceable   bool                   `protobuf:"varint,1,opt,name=serviceable,proto3" json:"serviceable,omitempty"`
+	ZoneId        string                 `protobuf:"bytes,2,opt,name=zone_id,json=zoneId,proto3" json:"zone_id,omitempty"`
+	Reason        string                 `protobuf:"bytes,3,opt,name=reason,proto3" json:"reason,omitempty"` // Why the address is not serviceable
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ServiceabilityResponse) Reset() {
+	*x = ServiceabilityResponse{}
+	mi := &file_order_service_proto_order_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ServiceabilityResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ServiceabilityResponse) ProtoMessage() {}
+
+func (x *ServiceabilityResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ServiceabilityResponse.ProtoReflect.Descriptor instead.
+func (*ServiceabilityResponse) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ServiceabilityResponse) GetServiceable() bool {
+	if x != nil {
+		return x.Serviceable
+	}
+	return false
+}
+
+func (x *ServiceabilityResponse) GetZoneId() string {
+	if x != nil {
+		return x.ZoneId
+	}
+	return ""
+}
+
+func (x *ServiceabilityResponse) GetReason() string {
+	if x != nil {
+		return x.Reason
+	}
+	return ""
+}
+
+type SetDeliveryTimeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OrderId       string                 `protobuf:"bytes,1,opt,name=order_id,json=orderId,proto3" json:"order_id,omitempty"`
+	DeliveryTime  *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=delivery_time,json=deliveryTime,proto3" json:"delivery_time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetDeliveryTimeRequest) Reset() {
+	*x = SetDeliveryTimeRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetDeliveryTimeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetDeliveryTimeRequest) ProtoMessage() {}
+
+func (x *SetDeliveryTimeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetDeliveryTimeRequest.ProtoReflect.Descriptor instead.
+func (*SetDeliveryTimeRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SetDeliveryTimeRequest) GetOrderId() string {
+	if x != nil {
+		return x.OrderId
+	}
+	return ""
+}
+
+func (x *SetDeliveryTimeRequest) GetDeliveryTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveryTime
+	}
+	return nil
+}
+
+type DeliverySlotsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	PostalCode    string                 `protobuf:"bytes,1,opt,name=postal_code,json=postalCode,proto3" json:"postal_code,omitempty"`
+	Date          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=date,proto3" json:"date,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliverySlotsRequest) Reset() {
+	*x = DeliverySlotsRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliverySlotsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliverySlotsRequest) ProtoMessage() {}
+
+func (x *DeliverySlotsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliverySlotsRequest.ProtoReflect.Descriptor instead.
+func (*DeliverySlotsRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *DeliverySlotsRequest) GetPostalCode() string {
+	if x != nil {
+		return x.PostalCode
+	}
+	return ""
+}
+
+func (x *DeliverySlotsRequest) GetDate() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+type DeliverySlot struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	StartTime       *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
+	EndTime         *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
+	Available       bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	Id              string                 `protobuf:"bytes,4,opt,name=id,proto3" json:"id,omitempty"`
+	DeliveryFee     float64                `protobuf:"fixed64,5,opt,name=delivery_fee,json=deliveryFee,proto3" json:"delivery_fee,omitempty"`
+	SurgeMultiplier float64                `protobuf:"fixed64,6,opt,name=surge_multiplier,json=surgeMultiplier,proto3" json:"surge_multiplier,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *DeliverySlot) Reset() {
+	*x = DeliverySlot{}
+	mi := &file_order_service_proto_order_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliverySlot) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliverySlot) ProtoMessage() {}
+
+func (x *DeliverySlot) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliverySlot.ProtoReflect.Descriptor instead.
+func (*DeliverySlot) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *DeliverySlot) GetStartTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartTime
+	}
+	return nil
+}
+
+func (x *DeliverySlot) GetEndTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.EndTime
+	}
+	return nil
+}
+
+func (x *DeliverySlot) GetAvailable() bool {
+	if x != nil {
+		return x.Available
+	}
+	return false
+}
+
+func (x *DeliverySlot) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *DeliverySlot) GetDeliveryFee() float64 {
+	if x != nil {
+		return x.DeliveryFee
+	}
+	return 0
+}
+
+func (x *DeliverySlot) GetSurgeMultiplier() float64 {
+	if x != nil {
+		return x.SurgeMultiplier
+	}
+	return 0
+}
+
+type DeliverySlotsResponse struct {
+	state                 protoimpl.MessageState `protogen:"open.v1"`
+	Slots                 []*DeliverySlot        `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"`
+	FreeDeliveryThreshold float64                `protobuf:"fixed64,2,opt,name=free_delivery_threshold,json=freeDeliveryThreshold,proto3" json:"free_delivery_threshold,omitempty"` // Zero when free delivery is not offered
+	MinimumOrderTotal     float64                `protobuf:"fixed64,3,opt,name=minimum_order_total,json=minimumOrderTotal,proto3" json:"minimum_order_total,omitempty"`
+	unknownFields         protoimpl.UnknownFields
+	sizeCache             protoimpl.SizeCache
+}
+
+func (x *DeliverySlotsResponse) Reset() {
+	*x = DeliverySlotsResponse{}
+	mi := &file_order_service_proto_order_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeliverySlotsRequest) String() string {
+func (x *DeliverySlotsResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeliverySlotsRequest) ProtoMessage() {}
+func (*DeliverySlotsResponse) ProtoMessage() {}
 
-func (x *DeliverySlotsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[10]
+func (x *DeliverySlotsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -688,49 +1334,166 @@ func (x *DeliverySlotsRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeliverySlotsRequest.ProtoReflect.Descriptor instead.
-func (*DeliverySlotsRequest) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{10}
+// Deprecated: Use DeliverySlotsResponse.ProtoReflect.Descriptor instead.
+func (*DeliverySlotsResponse) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{19}
 }
 
-func (x *DeliverySlotsRequest) GetPostalCode() string {
+func (x *DeliverySlotsResponse) GetSlots() []*DeliverySlot {
 	if x != nil {
-		return x.PostalCode
+		return x.Slots
+	}
+	return nil
+}
+
+func (x *DeliverySlotsResponse) GetFreeDeliveryThreshold() float64 {
+	if x != nil {
+		return x.FreeDeliveryThreshold
+	}
+	return 0
+}
+
+func (x *DeliverySlotsResponse) GetMinimumOrderTotal() float64 {
+	if x != nil {
+		return x.MinimumOrderTotal
+	}
+	return 0
+}
+
+type HoldDeliverySlotRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	SlotId        string                 `protobuf:"bytes,2,opt,name=slot_id,json=slotId,proto3" json:"slot_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *HoldDeliverySlotRequest) Reset() {
+	*x = HoldDeliverySlotRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *HoldDeliverySlotRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*HoldDeliverySlotRequest) ProtoMessage() {}
+
+func (x *HoldDeliverySlotRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use HoldDeliverySlotRequest.ProtoReflect.Descriptor instead.
+func (*HoldDeliverySlotRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *HoldDeliverySlotRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
 	}
 	return ""
 }
 
-func (x *DeliverySlotsRequest) GetDate() *timestamppb.Timestamp {
+func (x *HoldDeliverySlotRequest) GetSlotId() string {
 	if x != nil {
-		return x.Date
+		return x.SlotId
+	}
+	return ""
+}
+
+type DeliverySlotHold struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	SlotId        string                 `protobuf:"bytes,2,opt,name=slot_id,json=slotId,proto3" json:"slot_id,omitempty"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliverySlotHold) Reset() {
+	*x = DeliverySlotHold{}
+	mi := &file_order_service_proto_order_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliverySlotHold) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliverySlotHold) ProtoMessage() {}
+
+func (x *DeliverySlotHold) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliverySlotHold.ProtoReflect.Descriptor instead.
+func (*DeliverySlotHold) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *DeliverySlotHold) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *DeliverySlotHold) GetSlotId() string {
+	if x != nil {
+		return x.SlotId
+	}
+	return ""
+}
+
+func (x *DeliverySlotHold) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
 	}
 	return nil
 }
 
-type DeliverySlot struct {
+type ExportUserDataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	StartTime     *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=start_time,json=startTime,proto3" json:"start_time,omitempty"`
-	EndTime       *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=end_time,json=endTime,proto3" json:"end_time,omitempty"`
-	Available     bool                   `protobuf:"varint,3,opt,name=available,proto3" json:"available,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeliverySlot) Reset() {
-	*x = DeliverySlot{}
-	mi := &file_order_service_proto_order_proto_msgTypes[11]
+func (x *ExportUserDataRequest) Reset() {
+	*x = ExportUserDataRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeliverySlot) String() string {
+func (x *ExportUserDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeliverySlot) ProtoMessage() {}
+func (*ExportUserDataRequest) ProtoMessage() {}
 
-func (x *DeliverySlot) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[11]
+func (x *ExportUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -741,54 +1504,108 @@ func (x *DeliverySlot) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeliverySlot.ProtoReflect.Descriptor instead.
-func (*DeliverySlot) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{11}
+// Deprecated: Use ExportUserDataRequest.ProtoReflect.Descriptor instead.
+func (*ExportUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{22}
 }
 
-func (x *DeliverySlot) GetStartTime() *timestamppb.Timestamp {
+func (x *ExportUserDataRequest) GetUserId() string {
 	if x != nil {
-		return x.StartTime
+		return x.UserId
+	}
+	return ""
+}
+
+type UserDataExport struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Orders        []*Order               `protobuf:"bytes,2,rep,name=orders,proto3" json:"orders,omitempty"`
+	Addresses     []*DeliveryAddress     `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	ExportedAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=exported_at,json=exportedAt,proto3" json:"exported_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserDataExport) Reset() {
+	*x = UserDataExport{}
+	mi := &file_order_service_proto_order_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserDataExport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserDataExport) ProtoMessage() {}
+
+func (x *UserDataExport) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserDataExport.ProtoReflect.Descriptor instead.
+func (*UserDataExport) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *UserDataExport) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *UserDataExport) GetOrders() []*Order {
+	if x != nil {
+		return x.Orders
 	}
 	return nil
 }
 
-func (x *DeliverySlot) GetEndTime() *timestamppb.Timestamp {
+func (x *UserDataExport) GetAddresses() []*DeliveryAddress {
 	if x != nil {
-		return x.EndTime
+		return x.Addresses
 	}
 	return nil
 }
 
-func (x *DeliverySlot) GetAvailable() bool {
+func (x *UserDataExport) GetExportedAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Available
+		return x.ExportedAt
 	}
-	return false
+	return nil
 }
 
-type DeliverySlotsResponse struct {
+type EraseUserDataRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Slots         []*DeliverySlot        `protobuf:"bytes,1,rep,name=slots,proto3" json:"slots,omitempty"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeliverySlotsResponse) Reset() {
-	*x = DeliverySlotsResponse{}
-	mi := &file_order_service_proto_order_proto_msgTypes[12]
+func (x *EraseUserDataRequest) Reset() {
+	*x = EraseUserDataRequest{}
+	mi := &file_order_service_proto_order_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeliverySlotsResponse) String() string {
+func (x *EraseUserDataRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeliverySlotsResponse) ProtoMessage() {}
+func (*EraseUserDataRequest) ProtoMessage() {}
 
-func (x *DeliverySlotsResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_order_service_proto_order_proto_msgTypes[12]
+func (x *EraseUserDataRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -799,23 +1616,75 @@ func (x *DeliverySlotsResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeliverySlotsResponse.ProtoReflect.Descriptor instead.
-func (*DeliverySlotsResponse) Descriptor() ([]byte, []int) {
-	return file_order_service_proto_order_proto_rawDescGZIP(), []int{12}
+// Deprecated: Use EraseUserDataRequest.ProtoReflect.Descriptor instead.
+func (*EraseUserDataRequest) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *DeliverySlotsResponse) GetSlots() []*DeliverySlot {
+func (x *EraseUserDataRequest) GetUserId() string {
 	if x != nil {
-		return x.Slots
+		return x.UserId
 	}
-	return nil
+	return ""
+}
+
+type EraseUserDataResponse struct {
+	state               protoimpl.MessageState `protogen:"open.v1"`
+	OrdersPseudonymized int32                  `protobuf:"varint,1,opt,name=orders_pseudonymized,json=ordersPseudonymized,proto3" json:"orders_pseudonymized,omitempty"`
+	AddressesDeleted    int32                  `protobuf:"varint,2,opt,name=addresses_deleted,json=addressesDeleted,proto3" json:"addresses_deleted,omitempty"`
+	unknownFields       protoimpl.UnknownFields
+	sizeCache           protoimpl.SizeCache
+}
+
+func (x *EraseUserDataResponse) Reset() {
+	*x = EraseUserDataResponse{}
+	mi := &file_order_service_proto_order_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EraseUserDataResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EraseUserDataResponse) ProtoMessage() {}
+
+func (x *EraseUserDataResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_order_service_proto_order_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EraseUserDataResponse.ProtoReflect.Descriptor instead.
+func (*EraseUserDataResponse) Descriptor() ([]byte, []int) {
+	return file_order_service_proto_order_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *EraseUserDataResponse) GetOrdersPseudonymized() int32 {
+	if x != nil {
+		return x.OrdersPseudonymized
+	}
+	return 0
+}
+
+func (x *EraseUserDataResponse) GetAddressesDeleted() int32 {
+	if x != nil {
+		return x.AddressesDeleted
+	}
+	return 0
 }
 
 var File_order_service_proto_order_proto protoreflect.FileDescriptor
 
 const file_order_service_proto_order_proto_rawDesc = "" +
 	"\n" +
-	"\x1forder-service/proto/order.proto\x12\x05order\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xa7\x03\n" +
+	"\x1forder-service/proto/order.proto\x12\x05order\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a\x1cgoogle/api/annotations.proto\"\xe9\x03\n" +
 	"\x05Order\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12&\n" +
@@ -830,7 +1699,10 @@ const file_order_service_proto_order_proto_rawDesc = "" +
 	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
 	"updated_at\x18\n" +
-	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\"\xa9\x01\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12!\n" +
+	"\fdelivery_fee\x18\v \x01(\x01R\vdeliveryFee\x12\x1d\n" +
+	"\n" +
+	"courier_id\x18\f \x01(\tR\tcourierId\"\xa9\x01\n" +
 	"\tOrderItem\x12\x1d\n" +
 	"\n" +
 	"product_id\x18\x01 \x01(\tR\tproductId\x12!\n" +
@@ -839,7 +1711,7 @@ const file_order_service_proto_order_proto_rawDesc = "" +
 	"\n" +
 	"unit_price\x18\x04 \x01(\x01R\tunitPrice\x12\x1f\n" +
 	"\vtotal_price\x18\x05 \x01(\x01R\n" +
-	"totalPrice\"\xb7\x01\n" +
+	"totalPrice\"\x92\x03\n" +
 	"\x0fDeliveryAddress\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
@@ -848,16 +1720,47 @@ const file_order_service_proto_order_proto_rawDesc = "" +
 	"\x05state\x18\x05 \x01(\tR\x05state\x12\x18\n" +
 	"\acountry\x18\x06 \x01(\tR\acountry\x12\x1f\n" +
 	"\vpostal_code\x18\a \x01(\tR\n" +
-	"postalCode\"\xc0\x01\n" +
+	"postalCode\x12\x1b\n" +
+	"\tfull_name\x18\b \x01(\tR\bfullName\x12\x1c\n" +
+	"\tapartment\x18\t \x01(\tR\tapartment\x12\x14\n" +
+	"\x05phone\x18\n" +
+	" \x01(\tR\x05phone\x12\x1d\n" +
+	"\n" +
+	"is_default\x18\v \x01(\bR\tisDefault\x12+\n" +
+	"\blocation\x18\f \x01(\v2\x0f.order.GeoPointR\blocation\x12<\n" +
+	"\vpreferences\x18\r \x01(\v2\x1a.order.DeliveryPreferencesR\vpreferences\"\xde\x01\n" +
+	"\x13DeliveryPreferences\x12\"\n" +
+	"\finstructions\x18\x01 \x01(\tR\finstructions\x12\"\n" +
+	"\rleave_at_door\x18\x02 \x01(\bR\vleaveAtDoor\x12\x14\n" +
+	"\x05floor\x18\x03 \x01(\tR\x05floor\x12!\n" +
+	"\fhas_elevator\x18\x04 \x01(\bR\vhasElevator\x12%\n" +
+	"\x0econtact_method\x18\x05 \x01(\tR\rcontactMethod\x12\x1f\n" +
+	"\vaccess_code\x18\x06 \x01(\tR\n" +
+	"accessCode\"\xf8\x01\n" +
 	"\x12CreateOrderRequest\x12&\n" +
 	"\x05items\x18\x01 \x03(\v2\x10.order.OrderItemR\x05items\x12A\n" +
 	"\x10delivery_address\x18\x02 \x01(\v2\x16.order.DeliveryAddressR\x0fdeliveryAddress\x12?\n" +
-	"\rdelivery_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fdeliveryTime\",\n" +
+	"\rdelivery_time\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\fdeliveryTime\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12\x1d\n" +
+	"\n" +
+	"hold_token\x18\x05 \x01(\tR\tholdToken\",\n" +
 	"\x0fGetOrderRequest\x12\x19\n" +
-	"\border_id\x18\x01 \x01(\tR\aorderId\"M\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\"S\n" +
+	"\x11WatchOrderRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12#\n" +
+	"\rsince_version\x18\x02 \x01(\x04R\fsinceVersion\"\x96\x01\n" +
+	"\vOrderUpdate\x12\"\n" +
+	"\x05order\x18\x01 \x01(\v2\f.order.OrderR\x05order\x12\x18\n" +
+	"\aversion\x18\x02 \x01(\x04R\aversion\x12\x14\n" +
+	"\x05event\x18\x03 \x01(\tR\x05event\x123\n" +
+	"\asent_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\x06sentAt\"M\n" +
 	"\x18UpdateOrderStatusRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x16\n" +
-	"\x06status\x18\x02 \x01(\tR\x06status\"N\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\"P\n" +
+	"\x14AssignCourierRequest\x12\x19\n" +
+	"\border_id\x18\x01 \x01(\tR\aorderId\x12\x1d\n" +
+	"\n" +
+	"courier_id\x18\x02 \x01(\tR\tcourierId\"N\n" +
 	"\x14DeleteAddressRequest\x12\x1d\n" +
 	"\n" +
 	"address_id\x18\x01 \x01(\tR\taddressId\x12\x17\n" +
@@ -865,31 +1768,77 @@ const file_order_service_proto_order_proto_rawDesc = "" +
 	"\x14ListAddressesRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\"M\n" +
 	"\x15ListAddressesResponse\x124\n" +
-	"\taddresses\x18\x01 \x03(\v2\x16.order.DeliveryAddressR\taddresses\"t\n" +
+	"\taddresses\x18\x01 \x03(\v2\x16.order.DeliveryAddressR\taddresses\"D\n" +
+	"\bGeoPoint\x12\x1a\n" +
+	"\blatitude\x18\x01 \x01(\x01R\blatitude\x12\x1c\n" +
+	"\tlongitude\x18\x02 \x01(\x01R\tlongitude\"\x84\x01\n" +
+	"\x1aCheckServiceabilityRequest\x12\x1f\n" +
+	"\vpostal_code\x18\x01 \x01(\tR\n" +
+	"postalCode\x12\x18\n" +
+	"\acountry\x18\x02 \x01(\tR\acountry\x12+\n" +
+	"\blocation\x18\x03 \x01(\v2\x0f.order.GeoPointR\blocation\"k\n" +
+	"\x16ServiceabilityResponse\x12 \n" +
+	"\vserviceable\x18\x01 \x01(\bR\vserviceable\x12\x17\n" +
+	"\azone_id\x18\x02 \x01(\tR\x06zoneId\x12\x16\n" +
+	"\x06reason\x18\x03 \x01(\tR\x06reason\"t\n" +
 	"\x16SetDeliveryTimeRequest\x12\x19\n" +
 	"\border_id\x18\x01 \x01(\tR\aorderId\x12?\n" +
 	"\rdelivery_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\fdeliveryTime\"g\n" +
 	"\x14DeliverySlotsRequest\x12\x1f\n" +
 	"\vpostal_code\x18\x01 \x01(\tR\n" +
 	"postalCode\x12.\n" +
-	"\x04date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\"\x9e\x01\n" +
+	"\x04date\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04date\"\xfc\x01\n" +
 	"\fDeliverySlot\x129\n" +
 	"\n" +
 	"start_time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\tstartTime\x125\n" +
 	"\bend_time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\aendTime\x12\x1c\n" +
-	"\tavailable\x18\x03 \x01(\bR\tavailable\"B\n" +
+	"\tavailable\x18\x03 \x01(\bR\tavailable\x12\x0e\n" +
+	"\x02id\x18\x04 \x01(\tR\x02id\x12!\n" +
+	"\fdelivery_fee\x18\x05 \x01(\x01R\vdeliveryFee\x12)\n" +
+	"\x10surge_multiplier\x18\x06 \x01(\x01R\x0fsurgeMultiplier\"\xaa\x01\n" +
 	"\x15DeliverySlotsResponse\x12)\n" +
-	"\x05slots\x18\x01 \x03(\v2\x13.order.DeliverySlotR\x05slots2\x85\x05\n" +
-	"\fOrderService\x126\n" +
-	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\f.order.Order\x120\n" +
-	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\f.order.Order\x12B\n" +
-	"\x11UpdateOrderStatus\x12\x1f.order.UpdateOrderStatusRequest\x1a\f.order.Order\x12D\n" +
-	"\x12AddDeliveryAddress\x12\x16.order.DeliveryAddress\x1a\x16.order.DeliveryAddress\x12G\n" +
-	"\x15UpdateDeliveryAddress\x12\x16.order.DeliveryAddress\x1a\x16.order.DeliveryAddress\x12L\n" +
-	"\x15DeleteDeliveryAddress\x12\x1b.order.DeleteAddressRequest\x1a\x16.google.protobuf.Empty\x12R\n" +
-	"\x15ListDeliveryAddresses\x12\x1b.order.ListAddressesRequest\x1a\x1c.order.ListAddressesResponse\x12>\n" +
-	"\x0fSetDeliveryTime\x12\x1d.order.SetDeliveryTimeRequest\x1a\f.order.Order\x12V\n" +
-	"\x19GetAvailableDeliverySlots\x12\x1b.order.DeliverySlotsRequest\x1a\x1c.order.DeliverySlotsResponseB'Z%github.com/hsibAD/order-service/protob\x06proto3"
+	"\x05slots\x18\x01 \x03(\v2\x13.order.DeliverySlotR\x05slots\x126\n" +
+	"\x17free_delivery_threshold\x18\x02 \x01(\x01R\x15freeDeliveryThreshold\x12.\n" +
+	"\x13minimum_order_total\x18\x03 \x01(\x01R\x11minimumOrderTotal\"K\n" +
+	"\x17HoldDeliverySlotRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x17\n" +
+	"\aslot_id\x18\x02 \x01(\tR\x06slotId\"|\n" +
+	"\x10DeliverySlotHold\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x17\n" +
+	"\aslot_id\x18\x02 \x01(\tR\x06slotId\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"0\n" +
+	"\x15ExportUserDataRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"\xc2\x01\n" +
+	"\x0eUserDataExport\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12$\n" +
+	"\x06orders\x18\x02 \x03(\v2\f.order.OrderR\x06orders\x124\n" +
+	"\taddresses\x18\x03 \x03(\v2\x16.order.DeliveryAddressR\taddresses\x12;\n" +
+	"\vexported_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"exportedAt\"/\n" +
+	"\x14EraseUserDataRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\"w\n" +
+	"\x15EraseUserDataResponse\x121\n" +
+	"\x14orders_pseudonymized\x18\x01 \x01(\x05R\x13ordersPseudonymized\x12+\n" +
+	"\x11addresses_deleted\x18\x02 \x01(\x05R\x10addressesDeleted2\xf8\f\n" +
+	"\fOrderService\x12M\n" +
+	"\vCreateOrder\x12\x19.order.CreateOrderRequest\x1a\f.order.Order\"\x15\x82\xd3\xe4\x93\x02\x0f:\x01*\"\n" +
+	"/v1/orders\x12O\n" +
+	"\bGetOrder\x12\x16.order.GetOrderRequest\x1a\f.order.Order\"\x1d\x82\xd3\xe4\x93\x02\x17\x12\x15/v1/orders/{order_id}\x12k\n" +
+	"\x11UpdateOrderStatus\x12\x1f.order.UpdateOrderStatusRequest\x1a\f.order.Order\"'\x82\xd3\xe4\x93\x02!:\x01*2\x1c/v1/orders/{order_id}/status\x12d\n" +
+	"\rAssignCourier\x12\x1b.order.AssignCourierRequest\x1a\f.order.Order\"(\x82\xd3\xe4\x93\x02\":\x01*\x1a\x1d/v1/orders/{order_id}/courier\x12a\n" +
+	"\n" +
+	"WatchOrder\x12\x18.order.WatchOrderRequest\x1a\x12.order.OrderUpdate\"#\x82\xd3\xe4\x93\x02\x1d\x12\x1b/v1/orders/{order_id}/watch0\x01\x12n\n" +
+	"\x12AddDeliveryAddress\x12\x16.order.DeliveryAddress\x1a\x16.order.DeliveryAddress\"(\x82\xd3\xe4\x93\x02\":\x01*\"\x1d/v1/users/{user_id}/addresses\x12v\n" +
+	"\x15UpdateDeliveryAddress\x12\x16.order.DeliveryAddress\x1a\x16.order.DeliveryAddress\"-\x82\xd3\xe4\x93\x02':\x01*\x1a\"/v1/users/{user_id}/addresses/{id}\x12\x80\x01\n" +
+	"\x15DeleteDeliveryAddress\x12\x1b.order.DeleteAddressRequest\x1a\x16.google.protobuf.Empty\"2\x82\xd3\xe4\x93\x02,**/v1/users/{user_id}/addresses/{address_id}\x12y\n" +
+	"\x15ListDeliveryAddresses\x12\x1b.order.ListAddressesRequest\x1a\x1c.order.ListAddressesResponse\"%\x82\xd3\xe4\x93\x02\x1f\x12\x1d/v1/users/{user_id}/addresses\x12s\n" +
+	"\x13CheckServiceability\x12!.order.CheckServiceabilityRequest\x1a\x1d.order.ServiceabilityResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/serviceability\x12n\n" +
+	"\x0fSetDeliveryTime\x12\x1d.order.SetDeliveryTimeRequest\x1a\f.order.Order\".\x82\xd3\xe4\x93\x02(:\x01*\x1a#/v1/orders/{order_id}/delivery-time\x12r\n" +
+	"\x19GetAvailableDeliverySlots\x12\x1b.order.DeliverySlotsRequest\x1a\x1c.order.DeliverySlotsResponse\"\x1a\x82\xd3\xe4\x93\x02\x14\x12\x12/v1/delivery-slots\x12z\n" +
+	"\x10HoldDeliverySlot\x12\x1e.order.HoldDeliverySlotRequest\x1a\x17.order.DeliverySlotHold\"-\x82\xd3\xe4\x93\x02':\x01*\"\"/v1/delivery-slots/{slot_id}/holds\x12i\n" +
+	"\x0eExportUserData\x12\x1c.order.ExportUserDataRequest\x1a\x15.order.UserDataExport\"\"\x82\xd3\xe4\x93\x02\x1c\x12\x1a/v1/users/{user_id}/export\x12l\n" +
+	"\rEraseUserData\x12\x1b.order.EraseUserDataRequest\x1a\x1c.order.EraseUserDataResponse\" \x82\xd3\xe4\x93\x02\x1a*\x18/v1/users/{user_id}/dataB'Z%github.com/hsibAD/order-service/protob\x06proto3"
 
 var (
 	file_order_service_proto_order_proto_rawDescOnce sync.Once
@@ -903,62 +1852,96 @@ func file_order_service_proto_order_proto_rawDescGZIP() []byte {
 	return file_order_service_proto_order_proto_rawDescData
 }
 
-var file_order_service_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_order_service_proto_order_proto_msgTypes = make([]protoimpl.MessageInfo, 26)
 var file_order_service_proto_order_proto_goTypes = []any{
-	(*Order)(nil),                    // 0: order.Order
-	(*OrderItem)(nil),                // 1: order.OrderItem
-	(*DeliveryAddress)(nil),          // 2: order.DeliveryAddress
-	(*CreateOrderRequest)(nil),       // 3: order.CreateOrderRequest
-	(*GetOrderRequest)(nil),          // 4: order.GetOrderRequest
-	(*UpdateOrderStatusRequest)(nil), // 5: order.UpdateOrderStatusRequest
-	(*DeleteAddressRequest)(nil),     // 6: order.DeleteAddressRequest
-	(*ListAddressesRequest)(nil),     // 7: order.ListAddressesRequest
-	(*ListAddressesResponse)(nil),    // 8: order.ListAddressesResponse
-	(*SetDeliveryTimeRequest)(nil),   // 9: order.SetDeliveryTimeRequest
-	(*DeliverySlotsRequest)(nil),     // 10: order.DeliverySlotsRequest
-	(*DeliverySlot)(nil),             // 11: order.DeliverySlot
-	(*DeliverySlotsResponse)(nil),    // 12: order.DeliverySlotsResponse
-	(*timestamppb.Timestamp)(nil),    // 13: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),            // 14: google.protobuf.Empty
+	(*Order)(nil),                      // 0: order.Order
+	(*OrderItem)(nil),                  // 1: order.OrderItem
+	(*DeliveryAddress)(nil),            // 2: order.DeliveryAddress
+	(*DeliveryPreferences)(nil),        // 3: order.DeliveryPreferences
+	(*CreateOrderRequest)(nil),         // 4: order.CreateOrderRequest
+	(*GetOrderRequest)(nil),            // 5: order.GetOrderRequest
+	(*WatchOrderRequest)(nil),          // 6: order.WatchOrderRequest
+	(*OrderUpdate)(nil),                // 7: order.OrderUpdate
+	(*UpdateOrderStatusRequest)(nil),   // 8: order.UpdateOrderStatusRequest
+	(*AssignCourierRequest)(nil),       // 9: order.AssignCourierRequest
+	(*DeleteAddressRequest)(nil),       // 10: order.DeleteAddressRequest
+	(*ListAddressesRequest)(nil),       // 11: order.ListAddressesRequest
+	(*ListAddressesResponse)(nil),      // 12: order.ListAddressesResponse
+	(*GeoPoint)(nil),                   // 13: order.GeoPoint
+	(*CheckServiceabilityRequest)(nil), // 14: order.CheckServiceabilityRequest
+	(*ServiceabilityResponse)(nil),     // 15: order.ServiceabilityResponse
+	(*SetDeliveryTimeRequest)(nil),     // 16: order.SetDeliveryTimeRequest
+	(*DeliverySlotsRequest)(nil),       // 17: order.DeliverySlotsRequest
+	(*DeliverySlot)(nil),               // 18: order.DeliverySlot
+	(*DeliverySlotsResponse)(nil),      // 19: order.DeliverySlotsResponse
+	(*HoldDeliverySlotRequest)(nil),    // 20: order.HoldDeliverySlotRequest
+	(*DeliverySlotHold)(nil),           // 21: order.DeliverySlotHold
+	(*ExportUserDataRequest)(nil),      // 22: order.ExportUserDataRequest
+	(*UserDataExport)(nil),             // 23: order.UserDataExport
+	(*EraseUserDataRequest)(nil),       // 24: order.EraseUserDataRequest
+	(*EraseUserDataResponse)(nil),      // 25: order.EraseUserDataResponse
+	(*timestamppb.Timestamp)(nil),      // 26: google.protobuf.Timestamp
+	(*emptypb.Empty)(nil),              // 27: google.protobuf.Empty
 }
 var file_order_service_proto_order_proto_depIdxs = []int32{
 	1,  // 0: order.Order.items:type_name -> order.OrderItem
 	2,  // 1: order.Order.delivery_address:type_name -> order.DeliveryAddress
-	13, // 2: order.Order.delivery_time:type_name -> google.protobuf.Timestamp
-	13, // 3: order.Order.created_at:type_name -> google.protobuf.Timestamp
-	13, // 4: order.Order.updated_at:type_name -> google.protobuf.Timestamp
-	1,  // 5: order.CreateOrderRequest.items:type_name -> order.OrderItem
-	2,  // 6: order.CreateOrderRequest.delivery_address:type_name -> order.DeliveryAddress
-	13, // 7: order.CreateOrderRequest.delivery_time:type_name -> google.protobuf.Timestamp
-	2,  // 8: order.ListAddressesResponse.addresses:type_name -> order.DeliveryAddress
-	13, // 9: order.SetDeliveryTimeRequest.delivery_time:type_name -> google.protobuf.Timestamp
-	13, // 10: order.DeliverySlotsRequest.date:type_name -> google.protobuf.Timestamp
-	13, // 11: order.DeliverySlot.start_time:type_name -> google.protobuf.Timestamp
-	13, // 12: order.DeliverySlot.end_time:type_name -> google.protobuf.Timestamp
-	11, // 13: order.DeliverySlotsResponse.slots:type_name -> order.DeliverySlot
-	3,  // 14: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
-	4,  // 15: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
-	5,  // 16: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
-	2,  // 17: order.OrderService.AddDeliveryAddress:input_type -> order.DeliveryAddress
-	2,  // 18: order.OrderService.UpdateDeliveryAddress:input_type -> order.DeliveryAddress
-	6,  // 19: order.OrderService.DeleteDeliveryAddress:input_type -> order.DeleteAddressRequest
-	7,  // 20: order.OrderService.ListDeliveryAddresses:input_type -> order.ListAddressesRequest
-	9,  // 21: order.OrderService.SetDeliveryTime:input_type -> order.SetDeliveryTimeRequest
-	10, // 22: order.OrderService.GetAvailableDeliverySlots:input_type -> order.DeliverySlotsRequest
-	0,  // 23: order.OrderService.CreateOrder:output_type -> order.Order
-	0,  // 24: order.OrderService.GetOrder:output_type -> order.Order
-	0,  // 25: order.OrderService.UpdateOrderStatus:output_type -> order.Order
-	2,  // 26: order.OrderService.AddDeliveryAddress:output_type -> order.DeliveryAddress
-	2,  // 27: order.OrderService.UpdateDeliveryAddress:output_type -> order.DeliveryAddress
-	14, // 28: order.OrderService.DeleteDeliveryAddress:output_type -> google.protobuf.Empty
-	8,  // 29: order.OrderService.ListDeliveryAddresses:output_type -> order.ListAddressesResponse
-	0,  // 30: order.OrderService.SetDeliveryTime:output_type -> order.Order
-	12, // 31: order.OrderService.GetAvailableDeliverySlots:output_type -> order.DeliverySlotsResponse
-	23, // [23:32] is the sub-list for method output_type
-	14, // [14:23] is the sub-list for method input_type
-	14, // [14:14] is the sub-list for extension type_name
-	14, // [14:14] is the sub-list for extension extendee
-	0,  // [0:14] is the sub-list for field type_name
+	26, // 2: order.Order.delivery_time:type_name -> google.protobuf.Timestamp
+	26, // 3: order.Order.created_at:type_name -> google.protobuf.Timestamp
+	26, // 4: order.Order.updated_at:type_name -> google.protobuf.Timestamp
+	13, // 5: order.DeliveryAddress.location:type_name -> order.GeoPoint
+	3,  // 6: order.DeliveryAddress.preferences:type_name -> order.DeliveryPreferences
+	1,  // 7: order.CreateOrderRequest.items:type_name -> order.OrderItem
+	2,  // 8: order.CreateOrderRequest.delivery_address:type_name -> order.DeliveryAddress
+	26, // 9: order.CreateOrderRequest.delivery_time:type_name -> google.protobuf.Timestamp
+	0,  // 10: order.OrderUpdate.order:type_name -> order.Order
+	26, // 11: order.OrderUpdate.sent_at:type_name -> google.protobuf.Timestamp
+	2,  // 12: order.ListAddressesResponse.addresses:type_name -> order.DeliveryAddress
+	13, // 13: order.CheckServiceabilityRequest.location:type_name -> order.GeoPoint
+	26, // 14: order.SetDeliveryTimeRequest.delivery_time:type_name -> google.protobuf.Timestamp
+	26, // 15: order.DeliverySlotsRequest.date:type_name -> google.protobuf.Timestamp
+	26, // 16: order.DeliverySlot.start_time:type_name -> google.protobuf.Timestamp
+	26, // 17: order.DeliverySlot.end_time:type_name -> google.protobuf.Timestamp
+	18, // 18: order.DeliverySlotsResponse.slots:type_name -> order.DeliverySlot
+	26, // 19: order.DeliverySlotHold.expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 20: order.UserDataExport.orders:type_name -> order.Order
+	2,  // 21: order.UserDataExport.addresses:type_name -> order.DeliveryAddress
+	26, // 22: order.UserDataExport.exported_at:type_name -> google.protobuf.Timestamp
+	4,  // 23: order.OrderService.CreateOrder:input_type -> order.CreateOrderRequest
+	5,  // 24: order.OrderService.GetOrder:input_type -> order.GetOrderRequest
+	8,  // 25: order.OrderService.UpdateOrderStatus:input_type -> order.UpdateOrderStatusRequest
+	9,  // 26: order.OrderService.AssignCourier:input_type -> order.AssignCourierRequest
+	6,  // 27: order.OrderService.WatchOrder:input_type -> order.WatchOrderRequest
+	2,  // 28: order.OrderService.AddDeliveryAddress:input_type -> order.DeliveryAddress
+	2,  // 29: order.OrderService.UpdateDeliveryAddress:input_type -> order.DeliveryAddress
+	10, // 30: order.OrderService.DeleteDeliveryAddress:input_type -> order.DeleteAddressRequest
+	11, // 31: order.OrderService.ListDeliveryAddresses:input_type -> order.ListAddressesRequest
+	14, // 32: order.OrderService.CheckServiceability:input_type -> order.CheckServiceabilityRequest
+	16, // 33: order.OrderService.SetDeliveryTime:input_type -> order.SetDeliveryTimeRequest
+	17, // 34: order.OrderService.GetAvailableDeliverySlots:input_type -> order.DeliverySlotsRequest
+	20, // 35: order.OrderService.HoldDeliverySlot:input_type -> order.HoldDeliverySlotRequest
+	22, // 36: order.OrderService.ExportUserData:input_type -> order.ExportUserDataRequest
+	24, // 37: order.OrderService.EraseUserData:input_type -> order.EraseUserDataRequest
+	0,  // 38: order.OrderService.CreateOrder:output_type -> order.Order
+	0,  // 39: order.OrderService.GetOrder:output_type -> order.Order
+	0,  // 40: order.OrderService.UpdateOrderStatus:output_type -> order.Order
+	0,  // 41: order.OrderService.AssignCourier:output_type -> order.Order
+	7,  // 42: order.OrderService.WatchOrder:output_type -> order.OrderUpdate
+	2,  // 43: order.OrderService.AddDeliveryAddress:output_type -> order.DeliveryAddress
+	2,  // 44: order.OrderService.UpdateDeliveryAddress:output_type -> order.DeliveryAddress
+	27, // 45: order.OrderService.DeleteDeliveryAddress:output_type -> google.protobuf.Empty
+	12, // 46: order.OrderService.ListDeliveryAddresses:output_type -> order.ListAddressesResponse
+	15, // 47: order.OrderService.CheckServiceability:output_type -> order.ServiceabilityResponse
+	0,  // 48: order.OrderService.SetDeliveryTime:output_type -> order.Order
+	19, // 49: order.OrderService.GetAvailableDeliverySlots:output_type -> order.DeliverySlotsResponse
+	21, // 50: order.OrderService.HoldDeliverySlot:output_type -> order.DeliverySlotHold
+	23, // 51: order.OrderService.ExportUserData:output_type -> order.UserDataExport
+	25, // 52: order.OrderService.EraseUserData:output_type -> order.EraseUserDataResponse
+	38, // [38:53] is the sub-list for method output_type
+	23, // [23:38] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
 }
 
 func init() { file_order_service_proto_order_proto_init() }
@@ -972,7 +1955,7 @@ func file_order_service_proto_order_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_order_service_proto_order_proto_rawDesc), len(file_order_service_proto_order_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   13,
+			NumMessages:   26,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -23,12 +23,18 @@ const (
 	OrderService_CreateOrder_FullMethodName               = "/order.OrderService/CreateOrder"
 	OrderService_GetOrder_FullMethodName                  = "/order.OrderService/GetOrder"
 	OrderService_UpdateOrderStatus_FullMethodName         = "/order.OrderService/UpdateOrderStatus"
+	OrderService_AssignCourier_FullMethodName             = "/order.OrderService/AssignCourier"
+	OrderService_WatchOrder_FullMethodName                = "/order.OrderService/WatchOrder"
 	OrderService_AddDeliveryAddress_FullMethodName        = "/order.OrderService/AddDeliveryAddress"
 	OrderService_UpdateDeliveryAddress_FullMethodName     = "/order.OrderService/UpdateDeliveryAddress"
 	OrderService_DeleteDeliveryAddress_FullMethodName     = "/order.OrderService/DeleteDeliveryAddress"
 	OrderService_ListDeliveryAddresses_FullMethodName     = "/order.OrderService/ListDeliveryAddresses"
+	OrderService_CheckServiceability_FullMethodName       = "/order.OrderService/CheckServiceability"
 	OrderService_SetDeliveryTime_FullMethodName           = "/order.OrderService/SetDeliveryTime"
 	OrderService_GetAvailableDeliverySlots_FullMethodName = "/order.OrderService/GetAvailableDeliverySlots"
+	OrderService_HoldDeliverySlot_FullMethodName          = "/order.OrderService/HoldDeliverySlot"
+	OrderService_ExportUserData_FullMethodName            = "/order.OrderService/ExportUserData"
+	OrderService_EraseUserData_FullMethodName             = "/order.OrderService/EraseUserData"
 )
 
 // OrderServiceClient is the client API for OrderService service.
@@ -39,14 +45,24 @@ type OrderServiceClient interface {
 	CreateOrder(ctx context.Context, in *CreateOrderRequest, opts ...grpc.CallOption) (*Order, error)
 	GetOrder(ctx context.Context, in *GetOrderRequest, opts ...grpc.CallOption) (*Order, error)
 	UpdateOrderStatus(ctx context.Context, in *UpdateOrderStatusRequest, opts ...grpc.CallOption) (*Order, error)
+	// Hands the order to a courier, who then sees the address's access code
+	AssignCourier(ctx context.Context, in *AssignCourierRequest, opts ...grpc.CallOption) (*Order, error)
+	// Streams the order's state, then its state after every change, until it
+	// is delivered or cancelled
+	WatchOrder(ctx context.Context, in *WatchOrderRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderUpdate], error)
 	// Address Management
 	AddDeliveryAddress(ctx context.Context, in *DeliveryAddress, opts ...grpc.CallOption) (*DeliveryAddress, error)
 	UpdateDeliveryAddress(ctx context.Context, in *DeliveryAddress, opts ...grpc.CallOption) (*DeliveryAddress, error)
 	DeleteDeliveryAddress(ctx context.Context, in *DeleteAddressRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	ListDeliveryAddresses(ctx context.Context, in *ListAddressesRequest, opts ...grpc.CallOption) (*ListAddressesResponse, error)
+	CheckServiceability(ctx context.Context, in *CheckServiceabilityRequest, opts ...grpc.CallOption) (*ServiceabilityResponse, error)
 	// Delivery Time Management
 	SetDeliveryTime(ctx context.Context, in *SetDeliveryTimeRequest, opts ...grpc.CallOption) (*Order, error)
 	GetAvailableDeliverySlots(ctx context.Context, in *DeliverySlotsRequest, opts ...grpc.CallOption) (*DeliverySlotsResponse, error)
+	HoldDeliverySlot(ctx context.Context, in *HoldDeliverySlotRequest, opts ...grpc.CallOption) (*DeliverySlotHold, error)
+	// Data Privacy
+	ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*UserDataExport, error)
+	EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*EraseUserDataResponse, error)
 }
 
 type orderServiceClient struct {
@@ -87,6 +103,35 @@ func (c *orderServiceClient) UpdateOrderStatus(ctx context.Context, in *UpdateOr
 	return out, nil
 }
 
+func (c *orderServiceClient) AssignCourier(ctx context.Context, in *AssignCourierRequest, opts ...grpc.CallOption) (*Order, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Order)
+	err := c.cc.Invoke(ctx, OrderService_AssignCourier_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) WatchOrder(ctx context.Context, in *WatchOrderRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[OrderUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &OrderService_ServiceDesc.Streams[0], OrderService_WatchOrder_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchOrderRequest, OrderUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_WatchOrderClient = grpc.ServerStreamingClient[OrderUpdate]
+
 func (c *orderServiceClient) AddDeliveryAddress(ctx context.Context, in *DeliveryAddress, opts ...grpc.CallOption) (*DeliveryAddress, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(DeliveryAddress)
@@ -127,6 +172,16 @@ func (c *orderServiceClient) ListDeliveryAddresses(ctx context.Context, in *List
 	return out, nil
 }
 
+func (c *orderServiceClient) CheckServiceability(ctx context.Context, in *CheckServiceabilityRequest, opts ...grpc.CallOption) (*ServiceabilityResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ServiceabilityResponse)
+	err := c.cc.Invoke(ctx, OrderService_CheckServiceability_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *orderServiceClient) SetDeliveryTime(ctx context.Context, in *SetDeliveryTimeRequest, opts ...grpc.CallOption) (*Order, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(Order)
@@ -147,6 +202,36 @@ func (c *orderServiceClient) GetAvailableDeliverySlots(ctx context.Context, in *
 	return out, nil
 }
 
+func (c *orderServiceClient) HoldDeliverySlot(ctx context.Context, in *HoldDeliverySlotRequest, opts ...grpc.CallOption) (*DeliverySlotHold, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeliverySlotHold)
+	err := c.cc.Invoke(ctx, OrderService_HoldDeliverySlot_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) ExportUserData(ctx context.Context, in *ExportUserDataRequest, opts ...grpc.CallOption) (*UserDataExport, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserDataExport)
+	err := c.cc.Invoke(ctx, OrderService_ExportUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *orderServiceClient) EraseUserData(ctx context.Context, in *EraseUserDataRequest, opts ...grpc.CallOption) (*EraseUserDataResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EraseUserDataResponse)
+	err := c.cc.Invoke(ctx, OrderService_EraseUserData_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // OrderServiceServer is the server API for OrderService service.
 // All implementations must embed UnimplementedOrderServiceServer
 // for forward compatibility.
@@ -155,14 +240,24 @@ type OrderServiceServer interface {
 	CreateOrder(context.Context, *CreateOrderRequest) (*Order, error)
 	GetOrder(context.Context, *GetOrderRequest) (*Order, error)
 	UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*Order, error)
+	// Hands the order to a courier, who then sees the address's access code
+	AssignCourier(context.Context, *AssignCourierRequest) (*Order, error)
+	// Streams the order's state, then its state after every change, until it
+	// is delivered or cancelled
+	WatchOrder(*WatchOrderRequest, grpc.ServerStreamingServer[OrderUpdate]) error
 	// Address Management
 	AddDeliveryAddress(context.Context, *DeliveryAddress) (*DeliveryAddress, error)
 	UpdateDeliveryAddress(context.Context, *DeliveryAddress) (*DeliveryAddress, error)
 	DeleteDeliveryAddress(context.Context, *DeleteAddressRequest) (*emptypb.Empty, error)
 	ListDeliveryAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error)
+	CheckServiceability(context.Context, *CheckServiceabilityRequest) (*ServiceabilityResponse, error)
 	// Delivery Time Management
 	SetDeliveryTime(context.Context, *SetDeliveryTimeRequest) (*Order, error)
 	GetAvailableDeliverySlots(context.Context, *DeliverySlotsRequest) (*DeliverySlotsResponse, error)
+	HoldDeliverySlot(context.Context, *HoldDeliverySlotRequest) (*DeliverySlotHold, error)
+	// Data Privacy
+	ExportUserData(context.Context, *ExportUserDataRequest) (*UserDataExport, error)
+	EraseUserData(context.Context, *EraseUserDataRequest) (*EraseUserDataResponse, error)
 	mustEmbedUnimplementedOrderServiceServer()
 }
 
@@ -182,6 +277,12 @@ func (UnimplementedOrderServiceServer) GetOrder(context.Context, *GetOrderReques
 func (UnimplementedOrderServiceServer) UpdateOrderStatus(context.Context, *UpdateOrderStatusRequest) (*Order, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateOrderStatus not implemented")
 }
+func (UnimplementedOrderServiceServer) AssignCourier(context.Context, *AssignCourierRequest) (*Order, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AssignCourier not implemented")
+}
+func (UnimplementedOrderServiceServer) WatchOrder(*WatchOrderRequest, grpc.ServerStreamingServer[OrderUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method WatchOrder not implemented")
+}
 func (UnimplementedOrderServiceServer) AddDeliveryAddress(context.Context, *DeliveryAddress) (*DeliveryAddress, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method AddDeliveryAddress not implemented")
 }
@@ -194,12 +295,24 @@ func (UnimplementedOrderServiceServer) DeleteDeliveryAddress(context.Context, *D
 func (UnimplementedOrderServiceServer) ListDeliveryAddresses(context.Context, *ListAddressesRequest) (*ListAddressesResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method ListDeliveryAddresses not implemented")
 }
+func (UnimplementedOrderServiceServer) CheckServiceability(context.Context, *CheckServiceabilityRequest) (*ServiceabilityResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckServiceability not implemented")
+}
 func (UnimplementedOrderServiceServer) SetDeliveryTime(context.Context, *SetDeliveryTimeRequest) (*Order, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SetDeliveryTime not implemented")
 }
 func (UnimplementedOrderServiceServer) GetAvailableDeliverySlots(context.Context, *DeliverySlotsRequest) (*DeliverySlotsResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetAvailableDeliverySlots not implemented")
 }
+func (UnimplementedOrderServiceServer) HoldDeliverySlot(context.Context, *HoldDeliverySlotRequest) (*DeliverySlotHold, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method HoldDeliverySlot not implemented")
+}
+func (UnimplementedOrderServiceServer) ExportUserData(context.Context, *ExportUserDataRequest) (*UserDataExport, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportUserData not implemented")
+}
+func (UnimplementedOrderServiceServer) EraseUserData(context.Context, *EraseUserDataRequest) (*EraseUserDataResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EraseUserData not implemented")
+}
 func (UnimplementedOrderServiceServer) mustEmbedUnimplementedOrderServiceServer() {}
 func (UnimplementedOrderServiceServer) testEmbeddedByValue()                      {}
 
@@ -275,6 +388,35 @@ func _OrderService_UpdateOrderStatus_Handler(srv interface{}, ctx context.Contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_AssignCourier_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AssignCourierRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).AssignCourier(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_AssignCourier_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).AssignCourier(ctx, req.(*AssignCourierRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_WatchOrder_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchOrderRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(OrderServiceServer).WatchOrder(m, &grpc.GenericServerStream[WatchOrderRequest, OrderUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type OrderService_WatchOrderServer = grpc.ServerStreamingServer[OrderUpdate]
+
 func _OrderService_AddDeliveryAddress_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeliveryAddress)
 	if err := dec(in); err != nil {
@@ -347,6 +489,24 @@ func _OrderService_ListDeliveryAddresses_Handler(srv interface{}, ctx context.Co
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_CheckServiceability_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckServiceabilityRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).CheckServiceability(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_CheckServiceability_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).CheckServiceability(ctx, req.(*CheckServiceabilityRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _OrderService_SetDeliveryTime_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(SetDeliveryTimeRequest)
 	if err := dec(in); err != nil {
@@ -383,6 +543,60 @@ func _OrderService_GetAvailableDeliverySlots_Handler(srv interface{}, ctx contex
 	return interceptor(ctx, in, info, handler)
 }
 
+func _OrderService_HoldDeliverySlot_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(HoldDeliverySlotRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).HoldDeliverySlot(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_HoldDeliverySlot_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).HoldDeliverySlot(ctx, req.(*HoldDeliverySlotRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_ExportUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).ExportUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_ExportUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).ExportUserData(ctx, req.(*ExportUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _OrderService_EraseUserData_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EraseUserDataRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(OrderServiceServer).EraseUserData(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: OrderService_EraseUserData_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(OrderServiceServer).EraseUserData(ctx, req.(*EraseUserDataRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // OrderService_ServiceDesc is the grpc.ServiceDesc for OrderService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -402,6 +616,10 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateOrderStatus",
 			Handler:    _OrderService_UpdateOrderStatus_Handler,
 		},
+		{
+			MethodName: "AssignCourier",
+			Handler:    _OrderService_AssignCourier_Handler,
+		},
 		{
 			MethodName: "AddDeliveryAddress",
 			Handler:    _OrderService_AddDeliveryAddress_Handler,
@@ -418,6 +636,10 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "ListDeliveryAddresses",
 			Handler:    _OrderService_ListDeliveryAddresses_Handler,
 		},
+		{
+			MethodName: "CheckServiceability",
+			Handler:    _OrderService_CheckServiceability_Handler,
+		},
 		{
 			MethodName: "SetDeliveryTime",
 			Handler:    _OrderService_SetDeliveryTime_Handler,
@@ -426,7 +648,25 @@ var OrderService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetAvailableDeliverySlots",
 			Handler:    _OrderService_GetAvailableDeliverySlots_Handler,
 		},
+		{
+			MethodName: "HoldDeliverySlot",
+			Handler:    _OrderService_HoldDeliverySlot_Handler,
+		},
+		{
+			MethodName: "ExportUserData",
+			Handler:    _OrderService_ExportUserData_Handler,
+		},
+		{
+			MethodName: "EraseUserData",
+			Handler:    _OrderService_EraseUserData_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchOrder",
+			Handler:       _OrderService_WatchOrder_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "order-service/proto/order.proto",
 }